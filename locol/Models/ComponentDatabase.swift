@@ -41,7 +41,7 @@ class ComponentDatabase: ObservableObject {
         guard let dbQueue = dbQueue else { return [] }
         do {
             return try dbQueue.read { db in
-                try CollectorComponent.fetchAll(db)
+                try CollectorComponent.componentRows.fetchAll(db)
             }
         } catch {
             logger.error("Failed to fetch all components: \(String(describing: error))")
@@ -54,7 +54,7 @@ class ComponentDatabase: ObservableObject {
         guard let dbQueue = dbQueue else { return [] }
         do {
             return try dbQueue.read { db in
-                try CollectorComponent
+                try CollectorComponent.componentRows
                     .filter(CollectorComponent.Columns.type == type.rawValue)
                     .fetchAll(db)
             }
@@ -69,7 +69,7 @@ class ComponentDatabase: ObservableObject {
         guard let dbQueue = dbQueue else { return nil }
         do {
             return try dbQueue.read { db in
-                try CollectorComponent
+                try CollectorComponent.componentRows
                     .filter(CollectorComponent.Columns.name == name && CollectorComponent.Columns.type == type.rawValue)
                     .fetchOne(db)
             }
@@ -166,7 +166,7 @@ class ComponentDatabase: ObservableObject {
         guard let dbQueue = dbQueue else { return nil }
         do {
             return try dbQueue.read { db in
-                try Document.fetchOne(db)
+                try Document.current.fetchOne(db)
             }
         } catch {
             logger.error("Failed to fetch document: \(String(describing: error))")
@@ -181,7 +181,7 @@ class ComponentDatabase: ObservableObject {
 
         do {
             return try dbQueue.read { db in
-                try CollectorComponent.fetchAll(db).filter { component in
+                try CollectorComponent.componentRows.fetchAll(db).filter { component in
                     component.name.lowercased().contains(lowercaseQuery) ||
                     component.description?.lowercased().contains(lowercaseQuery) == true
                 }
@@ -199,10 +199,12 @@ class ComponentDatabase: ObservableObject {
         }
         do {
             return try dbQueue.read { db in
-                let components = try Int.fetchOne(db, sql: "SELECT COUNT(*) FROM components") ?? 0
-                let fields = try Int.fetchOne(db, sql: "SELECT COUNT(*) FROM fields") ?? 0
-                let constraints = try Int.fetchOne(db, sql: "SELECT COUNT(*) FROM constraints") ?? 0
-                let examples = try Int.fetchOne(db, sql: "SELECT COUNT(*) FROM examples") ?? 0
+                // Counts cover the current collector version only
+                let current = "SELECT id FROM components WHERE type != 'service' AND \(currentCollectorVersionSQL)"
+                let components = try Int.fetchOne(db, sql: "SELECT COUNT(*) FROM (\(current))") ?? 0
+                let fields = try Int.fetchOne(db, sql: "SELECT COUNT(*) FROM component_fields WHERE component_id IN (\(current))") ?? 0
+                let constraints = try Int.fetchOne(db, sql: "SELECT COUNT(*) FROM constraints WHERE component_id IN (\(current))") ?? 0
+                let examples = try Int.fetchOne(db, sql: "SELECT COUNT(*) FROM examples WHERE component_id IN (\(current))") ?? 0
                 return ComponentDatabaseStatistics(components: components, fields: fields, constraints: constraints, examples: examples)
             }
         } catch {
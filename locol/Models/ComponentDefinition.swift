@@ -46,6 +46,9 @@ enum ConfigControlType: Sendable {
 
 // MARK: - Document Schema
 
+/// Selects the rows of meta.collector_version, the newest of the versions stored side by side.
+let currentCollectorVersionSQL = "version = (SELECT value FROM meta WHERE key = 'collector_version')"
+
 /// Represents the document configuration (singleton)
 struct Document: Codable, Identifiable {
     let id: Int
@@ -99,6 +102,11 @@ extension Document: FetchableRecord, TableRecord {
 // MARK: - Database Columns
 
 extension Document {
+    /// The document of the current collector version
+    static var current: QueryInterfaceRequest<Document> {
+        Document.filter(sql: currentCollectorVersionSQL)
+    }
+
     enum Columns {
         static let id = Column("id")
         static let sectionsJSON = Column("sections_json")
@@ -158,6 +166,14 @@ extension CollectorComponent {
         static let description = Column("description")
         static let version = Column("version")
     }
+
+    /// Rows for real component types of the current collector version; the service section is
+    /// stored as a "service" pseudo-component.
+    static var componentRows: QueryInterfaceRequest<CollectorComponent> {
+        CollectorComponent
+            .filter(ComponentType.allCases.map(\.rawValue).contains(Columns.type))
+            .filter(sql: currentCollectorVersionSQL)
+    }
 }
 
 /// Represents a configuration field
@@ -242,9 +258,9 @@ struct Field: Codable, Identifiable, Hashable, Sendable {
     }
 }
 
-// GRDB mapping for Field
+// GRDB mapping for Field; the view expands shared definitions (confighttp.ClientConfig, ...) into each component
 extension Field: FetchableRecord, TableRecord {
-    static let databaseTableName = "fields"
+    static let databaseTableName = "component_fields"
 
     init(row: Row) {
         self.id = row["id"]
@@ -304,7 +320,7 @@ struct FieldPath: Codable, Identifiable, Hashable, Sendable {
 
 // GRDB mapping for FieldPath
 extension FieldPath: FetchableRecord, TableRecord {
-    static let databaseTableName = "field_paths"
+    static let databaseTableName = "component_field_paths"
 
     init(row: Row) {
         self.fieldId = row["field_id"]
@@ -709,7 +725,7 @@ struct DocumentRequest: ValueObservationQueryable {
     static var defaultValue: Document? { nil }
 
     func fetch(_ db: Database) throws -> Document? {
-        try Document.fetchOne(db)
+        try Document.current.fetchOne(db)
     }
 }
 
@@ -718,7 +734,7 @@ struct ComponentsRequest: ValueObservationQueryable {
     static var defaultValue: [CollectorComponent] { [] }
 
     func fetch(_ db: Database) throws -> [CollectorComponent] {
-        try CollectorComponent.fetchAll(db)
+        try CollectorComponent.componentRows.fetchAll(db)
     }
 }
 
@@ -729,7 +745,7 @@ struct ComponentsByTypeRequest: ValueObservationQueryable {
     static var defaultValue: [CollectorComponent] { [] }
 
     func fetch(_ db: Database) throws -> [CollectorComponent] {
-        try CollectorComponent
+        try CollectorComponent.componentRows
             .filter(CollectorComponent.Columns.type == componentType.rawValue)
             .fetchAll(db)
     }
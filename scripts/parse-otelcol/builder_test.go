@@ -0,0 +1,63 @@
+package main
+
+import (
+    "bytes"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "gopkg.in/yaml.v3"
+)
+
+// TestBuilderGolden emits ocb manifests for fixture components picked by --components and
+// by a config file, compared with testdata/golden/builder/<case>.yaml. Every gomod line
+// must pin the module to the extracted version.
+func TestBuilderGolden(t *testing.T) {
+    _, components := fixtureComponents(t)
+    idx := newSchemaIndex(&ExtractedData{Version: "0.123.0", Components: components})
+    config := filepath.Join(t.TempDir(), "collector.yaml")
+    if err := os.WriteFile(config, []byte(`receivers:
+  squash:
+  squash/2:
+processors:
+  enum:
+exporters:
+  alias:
+connectors:
+  pair:
+`), 0644); err != nil { t.Fatal(err) }
+    tests := []struct {
+        name  string
+        comps func() ([]*Component, error)
+    }{
+        {"components", func() ([]*Component, error) {
+            return componentsFromIDs(idx, []string{"receiver/optional", "processor/route", "exporter/constraint", "exporter/constraint/2"})
+        }},
+        {"config", func() ([]*Component, error) { return componentsFromConfigs(idx, []string{config}) }},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            comps, err := tt.comps()
+            if err != nil { t.Fatal(err) }
+            doc, err := builderManifest(comps, idx.data.Version, "otelcol-custom", "./otelcol-custom")
+            if err != nil { t.Fatal(err) }
+            var buf bytes.Buffer
+            enc := yaml.NewEncoder(&buf)
+            enc.SetIndent(2)
+            if err := enc.Encode(doc); err != nil { t.Fatal(err) }
+            _ = enc.Close()
+            checkGolden(t, filepath.Join("testdata", "golden", "builder", tt.name+".yaml"), buf.Bytes())
+            n := 0
+            for _, ln := range strings.Split(buf.String(), "\n") {
+                if !strings.Contains(ln, "gomod:") { continue }
+                n++
+                if !strings.HasSuffix(ln, " v0.123.0") { t.Errorf("gomod not pinned to v0.123.0: %s", strings.TrimSpace(ln)) }
+            }
+            if n == 0 { t.Error("no gomod lines") }
+        })
+    }
+    if _, err := componentsFromIDs(idx, []string{"receiver/nosuch/2", "processor/enum"}); err == nil || err.Error() != "unknown components: receiver/nosuch" {
+        t.Errorf("unknown component: err = %v", err)
+    }
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "go/parser"
+    "go/token"
+    "io"
+    "os"
+    "path/filepath"
+    "sort"
+    "strconv"
+    "strings"
+    "sync"
+)
+
+// extractCache stores extracted components on disk keyed by a hash of their sources,
+// so consecutive releases only re-extract components whose code (or imports) changed.
+type extractCache struct {
+    dir  string
+    salt string // hash of the extractor binary; a rebuilt extractor invalidates every entry
+    mods sync.Map // go.mod path -> *goModInfo
+}
+
+// goModInfo is the subset of a go.mod needed to resolve imports to sources or versions.
+type goModInfo struct {
+    dir      string
+    module   string
+    requires map[string]string // module path -> version
+    replaces map[string]string // module path -> local directory
+}
+
+// newExtractCache returns nil when dir is empty (caching disabled).
+func newExtractCache(dir string) (*extractCache, error) {
+    if dir == "" { return nil, nil }
+    if err := os.MkdirAll(dir, 0755); err != nil { return nil, err }
+    c := &extractCache{dir: dir}
+    if exe, err := os.Executable(); err == nil {
+        if h, err := hashFile(exe); err == nil { c.salt = h }
+    }
+    return c, nil
+}
+
+func (c *extractCache) entryPath(key string) string { return filepath.Join(c.dir, key+".json") }
+
+func (c *extractCache) load(key string) (*Component, bool) {
+    data, err := os.ReadFile(c.entryPath(key))
+    if err != nil { return nil, false }
+    var comp Component
+    if err := json.Unmarshal(data, &comp); err != nil { return nil, false }
+    return &comp, true
+}
+
+func (c *extractCache) store(key string, comp *Component) {
+    data, err := json.Marshal(comp)
+    if err != nil { return }
+    // Write then rename so a concurrent or interrupted run never reads a partial entry
+    tmp := c.entryPath(key) + ".tmp"
+    if err := os.WriteFile(tmp, data, 0644); err != nil { return }
+    _ = os.Rename(tmp, c.entryPath(key))
+}
+
+// key hashes everything extractComponent reads for a component: its directory tree
+// (Go sources, metadata.yaml, example configs) plus, transitively, every repo-local
+// package it imports. Imports of other modules contribute their go.mod version.
+func (c *extractCache) key(t extractTask) (string, error) {
+    h := sha256.New()
+    fmt.Fprintf(h, "salt %s\ntyped %t\ntype %s\nname %s\ncontrib %t\n", c.salt, *typed, t.typ, t.name, t.isContrib)
+    if err := hashTree(h, t.componentPath); err != nil { return "", err }
+
+    visited := map[string]bool{}
+    external := map[string]bool{}
+    var walk func(dir string, recursive bool) error
+    walk = func(dir string, recursive bool) error {
+        if visited[dir] { return nil }
+        visited[dir] = true
+        imports, err := dirImports(dir, recursive)
+        if err != nil { return err }
+        mod := c.goMod(dir)
+        for _, imp := range imports {
+            localDir, version := resolveImportSource(mod, imp)
+            if localDir != "" {
+                if visited[localDir] { continue }
+                if !strings.HasPrefix(localDir, t.componentPath+string(filepath.Separator)) {
+                    fmt.Fprintf(h, "pkg %s\n", imp)
+                    if err := hashGoFiles(h, localDir); err != nil { return err }
+                }
+                if err := walk(localDir, false); err != nil { return err }
+            } else if version != "" {
+                external[imp+"@"+version] = true
+            }
+        }
+        return nil
+    }
+    if err := walk(t.componentPath, true); err != nil { return "", err }
+    for _, e := range sortedKeys(external) { fmt.Fprintf(h, "ext %s\n", e) }
+    return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// goMod returns the parsed go.mod governing dir, or nil.
+func (c *extractCache) goMod(dir string) *goModInfo {
+    root, _ := findGoModRoot(dir)
+    path := filepath.Join(root, "go.mod")
+    if v, ok := c.mods.Load(path); ok { return v.(*goModInfo) }
+    info := parseGoMod(path)
+    c.mods.Store(path, info)
+    return info
+}
+
+// parseGoMod reads module, require and local replace directives (line-based, best-effort).
+func parseGoMod(path string) *goModInfo {
+    data, err := os.ReadFile(path)
+    if err != nil { return nil }
+    info := &goModInfo{dir: filepath.Dir(path), requires: map[string]string{}, replaces: map[string]string{}}
+    block := ""
+    for _, ln := range strings.Split(string(data), "\n") {
+        if i := strings.Index(ln, "//"); i >= 0 { ln = ln[:i] }
+        ln = strings.TrimSpace(ln)
+        switch {
+        case ln == "":
+            continue
+        case ln == ")":
+            block = ""
+            continue
+        case strings.HasSuffix(ln, "("):
+            block = strings.TrimSpace(strings.TrimSuffix(ln, "("))
+            continue
+        }
+        directive := block
+        if directive == "" {
+            parts := strings.SplitN(ln, " ", 2)
+            if len(parts) < 2 { continue }
+            directive, ln = parts[0], strings.TrimSpace(parts[1])
+        }
+        fields := strings.Fields(ln)
+        switch directive {
+        case "module":
+            info.module = strings.Trim(ln, `"`)
+        case "require":
+            if len(fields) >= 2 { info.requires[fields[0]] = fields[1] }
+        case "replace":
+            // old [v] => new [v]; only local directory replacements matter here
+            if i := indexOf(fields, "=>"); i > 0 && i+1 < len(fields) {
+                target := fields[i+1]
+                if strings.HasPrefix(target, ".") || filepath.IsAbs(target) {
+                    if !filepath.IsAbs(target) { target = filepath.Join(info.dir, target) }
+                    info.replaces[fields[0]] = target
+                }
+            }
+        }
+    }
+    return info
+}
+
+func indexOf(s []string, v string) int {
+    for i, x := range s {
+        if x == v { return i }
+    }
+    return -1
+}
+
+// resolveImportSource maps an import path to a local directory (same module or a
+// local replace) or to the required version of the module providing it.
+func resolveImportSource(mod *goModInfo, imp string) (string, string) {
+    if mod == nil { return "", "" }
+    if dir := modulePathDir(mod.module, mod.dir, imp); dir != "" { return dir, "" }
+    best := ""
+    for m := range mod.replaces {
+        if (imp == m || strings.HasPrefix(imp, m+"/")) && len(m) > len(best) { best = m }
+    }
+    if best != "" { return modulePathDir(best, mod.replaces[best], imp), "" }
+    for m := range mod.requires {
+        if (imp == m || strings.HasPrefix(imp, m+"/")) && len(m) > len(best) { best = m }
+    }
+    if best != "" { return "", best + "@" + mod.requires[best] }
+    return "", ""
+}
+
+func modulePathDir(module, dir, imp string) string {
+    if module == "" { return "" }
+    if imp == module { return dir }
+    if !strings.HasPrefix(imp, module+"/") { return "" }
+    sub := filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(imp, module+"/")))
+    if st, err := os.Stat(sub); err != nil || !st.IsDir() { return "" }
+    return sub
+}
+
+// dirImports lists imports of non-test Go files in dir (and subdirectories when recursive).
+func dirImports(dir string, recursive bool) ([]string, error) {
+    seen := map[string]bool{}
+    fset := token.NewFileSet()
+    err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+        if err != nil { return err }
+        if d.IsDir() {
+            if path != dir && (!recursive || skipCacheDir(d.Name())) { return filepath.SkipDir }
+            return nil
+        }
+        if !isSourceGoFile(d.Name()) { return nil }
+        f, err := parser.ParseFile(fset, path, nil, parser.ImportsOnly)
+        if err != nil { return nil }
+        for _, is := range f.Imports {
+            if p, err := strconv.Unquote(is.Path.Value); err == nil { seen[p] = true }
+        }
+        return nil
+    })
+    return sortedKeys(seen), err
+}
+
+// hashTree hashes the files extractComponent reads below a component directory.
+func hashTree(w io.Writer, dir string) error {
+    var files []string
+    err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+        if err != nil { return err }
+        if d.IsDir() {
+            if path != dir && d.Name() == "vendor" { return filepath.SkipDir }
+            return nil
+        }
+        name := d.Name()
+        if isSourceGoFile(name) || strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml") { files = append(files, path) }
+        return nil
+    })
+    if err != nil { return err }
+    return hashFiles(w, dir, files)
+}
+
+// hashGoFiles hashes the non-test Go files directly in dir.
+func hashGoFiles(w io.Writer, dir string) error {
+    entries, err := os.ReadDir(dir)
+    if err != nil { return err }
+    var files []string
+    for _, e := range entries {
+        if !e.IsDir() && isSourceGoFile(e.Name()) { files = append(files, filepath.Join(dir, e.Name())) }
+    }
+    return hashFiles(w, dir, files)
+}
+
+func hashFiles(w io.Writer, base string, files []string) error {
+    sort.Strings(files)
+    for _, f := range files {
+        data, err := os.ReadFile(f)
+        if err != nil { return err }
+        rel, _ := filepath.Rel(base, f)
+        fmt.Fprintf(w, "file %s %d\n", filepath.ToSlash(rel), len(data))
+        _, _ = w.Write(data)
+    }
+    return nil
+}
+
+func hashFile(path string) (string, error) {
+    f, err := os.Open(path)
+    if err != nil { return "", err }
+    defer f.Close()
+    h := sha256.New()
+    if _, err := io.Copy(h, f); err != nil { return "", err }
+    return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func isSourceGoFile(name string) bool {
+    return strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go")
+}
+
+// skipCacheDir reports directories whose Go files are never compiled into the component.
+func skipCacheDir(name string) bool {
+    return name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
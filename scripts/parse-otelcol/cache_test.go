@@ -0,0 +1,74 @@
+package main
+
+import (
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+    "testing"
+)
+
+// TestExtractCache runs extractTasks repeatedly over a copy of the fixture tree and checks
+// which components get a new cache entry: none when nothing changed, only the edited
+// component after a source edit, only its importers after an edit to a repo-local package,
+// and every component again under --typed, which keys entries separately.
+func TestExtractCache(t *testing.T) {
+    root := t.TempDir()
+    if err := os.CopyFS(root, os.DirFS(fixtureRoot)); err != nil { t.Fatal(err) }
+    cacheDir := t.TempDir()
+    cache, err := newExtractCache(cacheDir)
+    if err != nil { t.Fatal(err) }
+    tasks := componentTasks(root, false)
+    var all []string
+    seen := map[string]bool{}
+    var last []Component
+    // run extracts every task and returns the components stored under keys not seen before.
+    run := func() []string {
+        t.Helper()
+        resetPackageCache()
+        t.Cleanup(resetPackageCache)
+        last = extractTasks(tasks, cache, root)
+        if len(last) != len(tasks) { t.Fatalf("extracted %d components, want %d", len(last), len(tasks)) }
+        entries, err := os.ReadDir(cacheDir)
+        if err != nil { t.Fatal(err) }
+        var fresh []string
+        for _, e := range entries {
+            if seen[e.Name()] { continue }
+            seen[e.Name()] = true
+            c, ok := cache.load(strings.TrimSuffix(e.Name(), ".json"))
+            if !ok { t.Fatalf("unreadable cache entry %s", e.Name()) }
+            fresh = append(fresh, componentKey(c))
+        }
+        sort.Strings(fresh)
+        return fresh
+    }
+    edit := func(name string) {
+        t.Helper()
+        path := filepath.Join(root, filepath.FromSlash(name))
+        data, err := os.ReadFile(path)
+        if err != nil { t.Fatal(err) }
+        if err := os.WriteFile(path, append(data, "\n// edited\n"...), 0644); err != nil { t.Fatal(err) }
+    }
+    check := func(step string, got []string, want ...string) {
+        t.Helper()
+        if strings.Join(got, ",") != strings.Join(want, ",") { t.Errorf("%s: new cache entries %v, want %v", step, got, want) }
+    }
+
+    all = run()
+    if len(all) != len(tasks) { t.Fatalf("first run: %d cache entries for %d components", len(all), len(tasks)) }
+    extracted, err := json.Marshal(last)
+    if err != nil { t.Fatal(err) }
+    check("unchanged", run())
+    if reused, _ := json.Marshal(last); string(reused) != string(extracted) { t.Error("cached components differ from the extracted ones") }
+    edit("receiver/squashreceiver/config.go")
+    check("component edit", run(), "receiver/squash")
+    edit("config/configretry/configretry.go")
+    check("import edit", run(), "exporter/retry")
+
+    saved := *typed
+    *typed = true
+    defer func() { *typed = saved }()
+    check("typed", run(), all...)
+    check("typed unchanged", run())
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+    "bufio"
+    "fmt"
+    "os"
+    "strings"
+
+    "gopkg.in/yaml.v3"
+)
+
+// hasConfmapReference reports whether s contains a ${...} expansion (${env:VAR},
+// ${file:path}, ${VAR}, ...); "$$" escapes a literal "$".
+func hasConfmapReference(s string) bool {
+    for i := 0; i+1 < len(s); i++ {
+        if s[i] != '$' { continue }
+        if s[i+1] == '$' {
+            i++
+            continue
+        }
+        if s[i+1] == '{' && strings.Contains(s[i+2:], "}") { return true }
+    }
+    return false
+}
+
+// expandConfmap expands the ${...} references of a config value offline: env variables
+// (${env:VAR}, ${VAR}) come from env, else from their ":-" default. ok is false when a
+// reference cannot be resolved here (${file:...}, ${http:...}, or a variable without a
+// value). missing lists variables env (when given) does not set and that have no default.
+// whole reports a value made of a single reference, which the collector decodes as YAML.
+func expandConfmap(s string, env map[string]string) (out string, whole, ok bool, missing []string) {
+    var b strings.Builder
+    ok = true
+    for i := 0; i < len(s); {
+        if s[i] != '$' || i+1 == len(s) || (s[i+1] != '$' && s[i+1] != '{') {
+            b.WriteByte(s[i])
+            i++
+            continue
+        }
+        if s[i+1] == '$' {
+            b.WriteByte('$')
+            i += 2
+            continue
+        }
+        end := strings.IndexByte(s[i+2:], '}')
+        if end < 0 {
+            b.WriteString(s[i:])
+            break
+        }
+        ref := s[i+2 : i+2+end]
+        if i == 0 && i+3+end == len(s) { whole = true }
+        i += end + 3
+        scheme, rest := "", ref
+        if c := strings.Index(ref, ":"); c > 0 && !strings.HasPrefix(ref[c:], ":-") { scheme, rest = ref[:c], ref[c+1:] }
+        if scheme != "" && scheme != "env" {
+            ok = false
+            continue
+        }
+        name, def, hasDef := strings.Cut(rest, ":-")
+        if v, set := env[name]; set {
+            b.WriteString(v)
+        } else if hasDef {
+            b.WriteString(def)
+        } else {
+            if env != nil { missing = append(missing, name) }
+            ok = false
+        }
+    }
+    return b.String(), whole, ok, missing
+}
+
+// expandScalar resolves the references of a scalar config value into the node the
+// collector would decode: the YAML value of a whole reference, else a string. It returns
+// nil when the value cannot be resolved offline or expands to nothing.
+func (v *configValidator) expandScalar(n *yaml.Node, key string) *yaml.Node {
+    out, whole, ok, missing := expandConfmap(n.Value, v.opts.env)
+    for _, name := range missing { v.report(n, "warning", key, fmt.Sprintf("environment variable %s is not set in the env file and has no default", name)) }
+    if !ok { return nil }
+    expanded := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: out}
+    if whole {
+        var doc yaml.Node
+        if err := yaml.Unmarshal([]byte(out), &doc); err == nil {
+            if len(doc.Content) == 0 { return nil }
+            expanded = doc.Content[0]
+        }
+    }
+    if isNullNode(expanded) { return nil }
+    expanded.Line, expanded.Column = n.Line, n.Column
+    return expanded
+}
+
+// readEnvFile parses KEY=VALUE lines ("export " prefixes, # comments and quoted values
+// allowed), as written for docker --env-file or systemd EnvironmentFile.
+func readEnvFile(path string) (map[string]string, error) {
+    f, err := os.Open(path)
+    if err != nil { return nil, err }
+    defer f.Close()
+    env := map[string]string{}
+    sc := bufio.NewScanner(f)
+    for line := 1; sc.Scan(); line++ {
+        s := strings.TrimSpace(sc.Text())
+        if s == "" || strings.HasPrefix(s, "#") { continue }
+        k, val, ok := strings.Cut(strings.TrimPrefix(s, "export "), "=")
+        k = strings.TrimSpace(k)
+        if !ok || k == "" { return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, line) }
+        val = strings.TrimSpace(val)
+        if len(val) >= 2 && (val[0] == '"' || val[0] == '\'') && val[len(val)-1] == val[0] { val = val[1 : len(val)-1] }
+        env[k] = val
+    }
+    return env, sc.Err()
+}
+
+// configOverride is a collector --set flag: a "::"-separated key path and a YAML value.
+type configOverride struct {
+    arg   string
+    path  []string
+    value *yaml.Node
+}
+
+func parseConfigOverride(arg string) (configOverride, error) {
+    key, val, ok := strings.Cut(arg, "=")
+    if !ok { return configOverride{}, fmt.Errorf("invalid --set %q (expected key::path=value)", arg) }
+    path := strings.Split(key, "::")
+    for _, p := range path {
+        if p == "" { return configOverride{}, fmt.Errorf("invalid --set key %q", key) }
+    }
+    value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null"}
+    var doc yaml.Node
+    if err := yaml.Unmarshal([]byte(val), &doc); err != nil { return configOverride{}, fmt.Errorf("invalid --set value %q: %v", val, err) }
+    if len(doc.Content) > 0 { value = doc.Content[0] }
+    return configOverride{arg: arg, path: path, value: value}, nil
+}
+
+// applyConfigOverrides merges --set values into a config file that defines the entry they
+// target (the component for component sections, else the top-level key), so a file of a
+// multi-file config is not handed the overrides of its siblings. The returned map tags the
+// inserted values with their flag; they take the position of what they replace.
+func applyConfigOverrides(root *yaml.Node, overrides []configOverride) map[*yaml.Node]string {
+    tagged := map[*yaml.Node]string{}
+    for _, o := range overrides {
+        depth := 1
+        if sectionKind(o.path[0]) != "" && len(o.path) > 2 { depth = 2 }
+        if _, _, ok := lookupPath(root, o.path[:depth]); !ok { continue }
+        cur := root
+        for i, tok := range o.path {
+            keyNode, val := mappingValue(cur, tok)
+            if i == len(o.path)-1 {
+                nv := *o.value
+                nv.Line, nv.Column = cur.Line, cur.Column
+                if val != nil { nv.Line, nv.Column = val.Line, val.Column }
+                setMappingValue(cur, tok, &nv)
+                tagged[&nv] = o.arg
+                break
+            }
+            if val == nil || val.Kind != yaml.MappingNode {
+                line, col := cur.Line, cur.Column
+                if keyNode != nil { line, col = keyNode.Line, keyNode.Column }
+                val = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: line, Column: col}
+                setMappingValue(cur, tok, val)
+            }
+            cur = val
+        }
+    }
+    return tagged
+}
+
+// setMappingValue replaces the value of key in mapping m, or appends the pair.
+func setMappingValue(m *yaml.Node, key string, val *yaml.Node) {
+    for i := 0; i+1 < len(m.Content); i += 2 {
+        if m.Content[i].Value == key {
+            m.Content[i+1] = val
+            return
+        }
+    }
+    m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key, Line: val.Line, Column: val.Column}, val)
+}
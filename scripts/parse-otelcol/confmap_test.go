@@ -0,0 +1,41 @@
+package main
+
+import "testing"
+
+// TestValidateConfmap type-checks ${...} references by their env file values or defaults
+// and --set overrides against the fixture schemas.
+func TestValidateConfmap(t *testing.T) {
+    _, components := fixtureComponents(t)
+    idx := newSchemaIndex(&ExtractedData{Components: components})
+    config := `processors:
+  enum:
+    mode: ${env:MODE}
+    verbosity: ${env:VERBOSITY:-loud}
+exporters:
+  retry:
+    endpoint: ${file:/etc/otel/endpoint}
+    timeout: ${env:TIMEOUT:-soon}
+    retry_on_failure:
+      enabled: ${RETRY}
+      initial_interval: ${env:UNSET}
+      max_interval: ${env:INTERVAL}s
+    reconnect:
+      enabled: true
+`
+    var overrides []configOverride
+    for _, arg := range []string{"exporters::retry::reconnect::enabled=maybe", "processors::batch::timeout=1s"} {
+        o, err := parseConfigOverride(arg)
+        if err != nil { t.Fatal(err) }
+        overrides = append(overrides, o)
+    }
+    opts := validateOptions{env: map[string]string{"MODE": "turbo", "RETRY": "yes", "INTERVAL": "5"}, overrides: overrides}
+    issues, err := validateConfig(idx, "collector.yaml", []byte(config), opts)
+    if err != nil { t.Fatal(err) }
+    checkIssues(t, issues,
+        `collector.yaml:3:11: error: processors::enum: mode: invalid value "turbo"; expected one of: balanced, fast, safe (expanded from "${env:MODE}")`,
+        `collector.yaml:8:14: error: exporters::retry: timeout: invalid duration "soon" (expanded from "${env:TIMEOUT:-soon}")`,
+        `collector.yaml:10:16: error: exporters::retry: retry_on_failure.enabled: expected a bool, got str "yes" (expanded from "${RETRY}")`,
+        "collector.yaml:11:25: warning: exporters::retry: retry_on_failure.initial_interval: environment variable UNSET is not set in the env file and has no default",
+        `collector.yaml:14:16: error: exporters::retry: reconnect.enabled: expected a bool, got str "maybe" (from --set exporters::retry::reconnect::enabled=maybe)`,
+    )
+}
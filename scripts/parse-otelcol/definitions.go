@@ -0,0 +1,215 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "strings"
+)
+
+// Definition is a shared config struct (confighttp.ClientConfig, configretry.BackOffConfig, ...)
+// emitted once in ExtractedData.Definitions instead of inline in every component embedding it.
+type Definition struct {
+    Package string        `json:"package"` // import path
+    Type    string        `json:"type"`
+    // Fields as most embedding components extract them, path tokens relative to the embedding path
+    Fields  []ConfigField `json:"fields"`
+}
+
+// fieldOrigin is a named struct a field was extracted through and the path it is embedded at.
+type fieldOrigin struct {
+    pkg, typ string
+    path     []string
+}
+
+// recordOrigin notes on fields, extracted from struct typ of ctx at prefix, that they came through it.
+func recordOrigin(ctx *packageContext, typ, prefix string, fields []ConfigField) {
+    if ctx.path == "" { return }
+    o := fieldOrigin{pkg: ctx.path, typ: typ, path: makePathTokens(prefix)}
+    for i := range fields { fields[i].origins = append(fields[i].origins, o) }
+}
+
+// applyFieldOrigins sets DeclaredIn/DeclaredPath from the innermost struct a field came
+// through, and Shared/SharedPath from the outermost one declared outside the component
+// package own and its subpackages (internal/metadata, ...).
+func applyFieldOrigins(own string, fields []ConfigField) {
+    if own == "" { return }
+    for i := range fields {
+        f := &fields[i]
+        if len(f.origins) > 0 && hasTokenPrefix(f.PathTokens, f.origins[0].path) {
+            f.DeclaredIn, f.DeclaredPath = f.origins[0].pkg+"."+f.origins[0].typ, f.origins[0].path
+        }
+        for j := len(f.origins) - 1; j >= 0; j-- {
+            o := f.origins[j]
+            if o.pkg == own || strings.HasPrefix(o.pkg, own+"/") { continue }
+            if hasTokenPrefix(f.PathTokens, o.path) { f.Shared, f.SharedPath = o.pkg+"."+o.typ, o.path }
+            break
+        }
+    }
+}
+
+// sharedBlock is one embedding of a shared struct in a component: the fields carrying the
+// same Shared/SharedPath, keyed by their path relative to the embedding path.
+type sharedBlock struct {
+    at     int // index of the first field, where the "$ref" field goes
+    path   []string
+    keys   []string
+    fields map[string]ConfigField
+    dup    bool // two fields share a relative path; kept inline
+}
+
+// factorDefinitions moves every shared struct embedding out of the components (and the
+// service) into d.Definitions. The definition keeps the most common form of each field;
+// the "$ref" field left in the component lists where its copy differs.
+func factorDefinitions(d *ExtractedData) {
+    comps := make([]*Component, 0, len(d.Components)+1)
+    for i := range d.Components { comps = append(comps, &d.Components[i]) }
+    if d.Service != nil { comps = append(comps, d.Service) }
+
+    blocks := make([]map[string]*sharedBlock, len(comps)) // per component, by Shared + path
+    uses := map[string][]*sharedBlock{}
+    var order []string
+    for ci, c := range comps {
+        blocks[ci] = map[string]*sharedBlock{}
+        for i, f := range c.Config.Fields {
+            if f.Shared == "" { continue }
+            bk := f.Shared + "\x00" + strings.Join(f.SharedPath, "\x00")
+            b := blocks[ci][bk]
+            if b == nil {
+                b = &sharedBlock{at: i, path: f.SharedPath, fields: map[string]ConfigField{}}
+                blocks[ci][bk] = b
+                if uses[f.Shared] == nil { order = append(order, f.Shared) }
+                uses[f.Shared] = append(uses[f.Shared], b)
+            }
+            rel := f
+            rel.PathTokens = relativeTokens(f.PathTokens, f.SharedPath)
+            rel.Shared, rel.SharedPath = "", nil
+            if rel.DeclaredIn != "" { rel.DeclaredPath = relativeTokens(f.DeclaredPath, f.SharedPath) }
+            k := strings.Join(rel.PathTokens, ".")
+            if _, ok := b.fields[k]; ok { b.dup = true }
+            b.fields[k] = rel
+            b.keys = append(b.keys, k)
+        }
+    }
+    if len(order) == 0 { return }
+
+    refs := map[*sharedBlock]ConfigField{}
+    d.Definitions = map[string]Definition{}
+    for _, key := range order {
+        dot := strings.LastIndex(key, ".")
+        def := Definition{Package: key[:dot], Type: key[dot+1:]}
+        counts := map[string]map[string]int{}
+        var keys []string
+        for _, b := range uses[key] {
+            if b.dup { continue }
+            for _, k := range b.keys {
+                if counts[k] == nil {
+                    counts[k] = map[string]int{}
+                    keys = append(keys, k)
+                }
+                counts[k][fieldJSON(b.fields[k])]++
+            }
+        }
+        if len(keys) == 0 { continue }
+        canonical := map[string]string{}
+        for _, k := range keys {
+            var best ConfigField
+            n := 0
+            for _, b := range uses[key] {
+                f, ok := b.fields[k]
+                if !ok || b.dup { continue }
+                if c := counts[k][fieldJSON(f)]; c > n { best, n = f, c }
+            }
+            canonical[k] = fieldJSON(best)
+            def.Fields = append(def.Fields, best)
+        }
+        d.Definitions[key] = def
+        for _, b := range uses[key] {
+            if b.dup { continue }
+            ref := ConfigField{Name: def.Type, Type: "object", PathTokens: b.path, Ref: key}
+            for _, k := range b.keys {
+                if fieldJSON(b.fields[k]) != canonical[k] { ref.Overrides = append(ref.Overrides, b.fields[k]) }
+            }
+            for _, k := range keys {
+                if _, ok := b.fields[k]; !ok { ref.Omit = append(ref.Omit, k) }
+            }
+            refs[b] = ref
+        }
+    }
+
+    for ci, c := range comps {
+        fields := make([]ConfigField, 0, len(c.Config.Fields))
+        for i, f := range c.Config.Fields {
+            if f.Shared == "" {
+                fields = append(fields, f)
+                continue
+            }
+            b := blocks[ci][f.Shared+"\x00"+strings.Join(f.SharedPath, "\x00")]
+            ref, ok := refs[b]
+            switch {
+            case !ok:
+                f.Shared, f.SharedPath = "", nil
+                fields = append(fields, f)
+            case i == b.at:
+                fields = append(fields, ref)
+            }
+        }
+        c.Config.Fields = fields
+    }
+}
+
+// expandDefinitions replaces every "$ref" field by the definition's fields under its path,
+// applying the component's overrides and omissions. Expanded fields carry Shared/SharedPath
+// as extracted.
+func expandDefinitions(d *ExtractedData) error {
+    comps := make([]*Component, 0, len(d.Components)+1)
+    for i := range d.Components { comps = append(comps, &d.Components[i]) }
+    if d.Service != nil { comps = append(comps, d.Service) }
+    for _, c := range comps {
+        var fields []ConfigField
+        for i, f := range c.Config.Fields {
+            if f.Ref == "" {
+                if fields != nil { fields = append(fields, f) }
+                continue
+            }
+            if fields == nil { fields = append([]ConfigField{}, c.Config.Fields[:i]...) }
+            def, ok := d.Definitions[f.Ref]
+            if !ok { return fmt.Errorf("%s: unknown definition %q", componentKey(c), f.Ref) }
+            overrides := map[string]ConfigField{}
+            for _, o := range f.Overrides { overrides[strings.Join(o.PathTokens, ".")] = o }
+            omit := map[string]bool{}
+            for _, k := range f.Omit { omit[k] = true }
+            place := func(rel ConfigField) {
+                rel.PathTokens = append(append([]string{}, f.PathTokens...), rel.PathTokens...)
+                if rel.DeclaredIn != "" { rel.DeclaredPath = append(append([]string{}, f.PathTokens...), rel.DeclaredPath...) }
+                rel.Shared, rel.SharedPath = f.Ref, f.PathTokens
+                fields = append(fields, rel)
+            }
+            for _, df := range def.Fields {
+                k := strings.Join(df.PathTokens, ".")
+                if o, ok := overrides[k]; ok {
+                    place(o)
+                    delete(overrides, k)
+                } else if !omit[k] {
+                    place(df)
+                }
+            }
+            // Overrides the definition has no field for
+            for _, o := range f.Overrides {
+                if _, ok := overrides[strings.Join(o.PathTokens, ".")]; ok { place(o) }
+            }
+        }
+        if fields != nil { c.Config.Fields = fields }
+    }
+    return nil
+}
+
+// relativeTokens strips prefix (which tokens starts with) from tokens; nil when nothing is left.
+func relativeTokens(tokens, prefix []string) []string {
+    if len(tokens) <= len(prefix) { return nil }
+    return append([]string{}, tokens[len(prefix):]...)
+}
+
+func fieldJSON(f ConfigField) string {
+    b, _ := json.Marshal(f)
+    return string(b)
+}
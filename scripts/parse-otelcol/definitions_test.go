@@ -0,0 +1,32 @@
+package main
+
+import (
+    "encoding/json"
+    "path/filepath"
+    "testing"
+)
+
+// TestDefinitionsGolden factors the shared config structs out of the fixture components and
+// checks that expanding the "$ref" fields gives back the extracted field lists.
+func TestDefinitionsGolden(t *testing.T) {
+    root, components := fixtureComponents(t)
+    d := &ExtractedData{Components: components, Service: extractServiceComponent(root)}
+    want, err := json.Marshal(d)
+    if err != nil { t.Fatal(err) }
+    factorDefinitions(d)
+    var refs []ConfigField
+    for _, c := range d.Components {
+        for _, f := range c.Config.Fields {
+            if f.Ref != "" { refs = append(refs, f) }
+        }
+    }
+    got, err := json.MarshalIndent(map[string]any{"definitions": d.Definitions, "refs": refs}, "", "  ")
+    if err != nil { t.Fatal(err) }
+    checkGolden(t, filepath.Join("testdata", "golden", "definitions.json"), append(got, '\n'))
+
+    if err := expandDefinitions(d); err != nil { t.Fatal(err) }
+    d.Definitions = nil
+    expanded, err := json.Marshal(d)
+    if err != nil { t.Fatal(err) }
+    if string(expanded) != string(want) { t.Error("expanded components differ from the extracted ones") }
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+    "go/ast"
+    "go/token"
+    "regexp"
+    "strconv"
+    "strings"
+)
+
+var (
+    // deprecatedRe matches prose such as "is deprecated" in Validate/Unmarshal messages.
+    // Doc comments only count a "Deprecated:" paragraph (see deprecationFromDoc).
+    deprecatedRe = regexp.MustCompile(`(?i)(^|[^\w-])deprecat(ed|ion)\b`)
+    // replacementRes capture the suggested key after a deprecation marker, most specific first.
+    replacementRes = []*regexp.Regexp{
+        regexp.MustCompile("(?i)\\b(?:use|set|configure)\\s+(?:the\\s+)?([`\"'])([\\w.:\\[\\]-]+)[`\"']"),
+        regexp.MustCompile("(?i)\\b(?:in favou?r of|replaced (?:by|with)|superseded by|moved to)\\s+(?:the\\s+)?([`\"']?)([\\w.:\\[\\]-]+)[`\"']?"),
+        regexp.MustCompile("(?i)\\b(?:use|set|configure)\\s+(?:the\\s+)?()([\\w.:\\[\\]-]+)\\s+(?:instead|field|setting|option|key)"),
+    }
+)
+
+// deprecationFromText reports whether a Validate/Unmarshal message marks a setting deprecated
+// and the replacement it names, if any. quoted is true when the replacement was written as
+// `key` or "key".
+func deprecationFromText(text string) (deprecated bool, replacement string, quoted bool) {
+    loc := deprecatedRe.FindStringIndex(text)
+    if loc == nil { return false, "", false }
+    replacement, quoted = replacementFromText(text[loc[1]:])
+    return true, replacement, quoted
+}
+
+// deprecationFromDoc is deprecationFromText for field comments, which follow the Go
+// convention: only a paragraph starting with "Deprecated:" marks the field, so prose that
+// merely mentions a deprecation does not.
+func deprecationFromDoc(field *ast.Field) (deprecated bool, replacement string, quoted bool) {
+    for _, g := range []*ast.CommentGroup{field.Doc, field.Comment} {
+        if g == nil { continue }
+        lines := strings.Split(g.Text(), "\n")
+        for i, l := range lines {
+            if !strings.HasPrefix(strings.TrimSpace(l), "Deprecated:") { continue }
+            para := []string{strings.TrimPrefix(strings.TrimSpace(l), "Deprecated:")}
+            for _, next := range lines[i+1:] {
+                if strings.TrimSpace(next) == "" { break }
+                para = append(para, strings.TrimSpace(next))
+            }
+            replacement, quoted = replacementFromText(strings.Join(para, " "))
+            return true, replacement, quoted
+        }
+    }
+    return false, "", false
+}
+
+// replacementFromText returns the key suggested in the text following a deprecation marker.
+func replacementFromText(rest string) (string, bool) {
+    for _, re := range replacementRes {
+        if m := re.FindStringSubmatch(rest); m != nil {
+            return strings.TrimRight(strings.ReplaceAll(m[2], "::", "."), "."), m[1] != ""
+        }
+    }
+    return "", false
+}
+
+// applyDeprecationHints marks fields that Validate() or Unmarshal() treat as deprecated:
+// an if statement whose body carries a "deprecated" message flags the keys tested in its
+// condition (cfg.Old != "" or conf.IsSet("old")). Replacement names are then resolved
+// to the YAML keys of extracted fields.
+func applyDeprecationHints(ctx *packageContext, rootName string, fields []ConfigField) {
+    index := map[string]int{}
+    for i, f := range fields { index[f.MapStructure] = i }
+    for _, file := range ctx.files {
+        for _, decl := range file.Decls {
+            fd, ok := decl.(*ast.FuncDecl)
+            if !ok || fd.Recv == nil || fd.Body == nil || (fd.Name.Name != "Validate" && fd.Name.Name != "Unmarshal") { continue }
+            if len(fd.Recv.List) == 0 || len(fd.Recv.List[0].Names) == 0 { continue }
+            recv, conf := fd.Recv.List[0].Names[0].Name, confParamName(fd)
+            ast.Inspect(fd.Body, func(n ast.Node) bool {
+                ifs, ok := n.(*ast.IfStmt)
+                if !ok { return true }
+                msg := deprecationMessage(ifs.Body)
+                if msg == "" { return true }
+                _, replacement, quoted := deprecationFromText(msg)
+                for _, k := range conditionKeys(ctx, rootName, recv, conf, ifs.Cond) {
+                    i, ok := index[k]
+                    if !ok { continue }
+                    fields[i].Deprecated = true
+                    if fields[i].Replacement == "" && replacement != "" {
+                        fields[i].Replacement, fields[i].replacementQuoted = replacement, quoted
+                    }
+                }
+                return true
+            })
+        }
+    }
+    for i := range fields {
+        if fields[i].Replacement == "" { continue }
+        r, ok := resolveReplacement(fields, &fields[i])
+        // Unquoted prose ("use the routing connector") is only kept when it names a field
+        if !ok && !fields[i].replacementQuoted { r = "" }
+        fields[i].Replacement = r
+    }
+}
+
+// deprecationMessage returns the first string literal in body mentioning deprecation.
+func deprecationMessage(body *ast.BlockStmt) string {
+    msg := ""
+    ast.Inspect(body, func(n ast.Node) bool {
+        if msg != "" { return false }
+        if bl, ok := n.(*ast.BasicLit); ok && bl.Kind == token.STRING {
+            if s, err := strconv.Unquote(bl.Value); err == nil && deprecatedRe.MatchString(s) { msg = s }
+        }
+        return true
+    })
+    return msg
+}
+
+// conditionKeys maps the config accesses in an if condition to YAML keys: selectors on the
+// receiver (cfg.Endpoint) and literal keys tested on the confmap (conf.IsSet("endpoint")).
+func conditionKeys(ctx *packageContext, rootName, recv, conf string, cond ast.Expr) []string {
+    var keys []string
+    ast.Inspect(cond, func(n ast.Node) bool {
+        switch e := n.(type) {
+        case *ast.CallExpr:
+            sel, ok := e.Fun.(*ast.SelectorExpr)
+            if !ok || conf == "" || !confKeyMethods[sel.Sel.Name] { return true }
+            if x, ok := sel.X.(*ast.Ident); ok && x.Name == conf {
+                if k, ok := callArgString(e); ok { keys = append(keys, strings.ReplaceAll(k, "::", ".")) }
+                return false
+            }
+        case *ast.SelectorExpr:
+            path := selectorPath(e)
+            if len(path) < 2 || path[0] != recv { return true }
+            if k := yamlKeyFromSelector(ctx, rootName, e); k != "" { keys = append(keys, k) }
+            return false
+        }
+        return true
+    })
+    return dedupe(keys)
+}
+
+// resolveReplacement maps a replacement named in a comment or message (a YAML key, a key
+// relative to the deprecated field's parent, or a Go field name) to an extracted field's
+// YAML key. ok is false (and the name is returned as written) when nothing matches.
+func resolveReplacement(fields []ConfigField, f *ConfigField) (string, bool) {
+    r := f.Replacement
+    parent := ""
+    if n := len(f.PathTokens); n > 1 { parent = strings.Join(f.PathTokens[:n-1], ".") + "." }
+    for _, cand := range []string{r, parent + r} {
+        for i := range fields {
+            if fields[i].MapStructure == cand { return cand, true }
+        }
+    }
+    for i := range fields {
+        g := &fields[i]
+        if g.Name == r && strings.HasPrefix(g.MapStructure, parent) { return g.MapStructure, true }
+    }
+    // A section rather than a leaf, e.g. "protocols.grpc"
+    for i := range fields {
+        if strings.HasPrefix(fields[i].MapStructure, parent+r+".") { return parent + r, true }
+    }
+    return r, false
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+    "encoding/json"
+    "flag"
+    "fmt"
+    "io"
+    "os"
+    "reflect"
+    "sort"
+    "strings"
+)
+
+// SchemaDiff is a version-to-version changelog of extracted component schemas.
+type SchemaDiff struct {
+    From              string          `json:"from"`
+    To                string          `json:"to"`
+    AddedComponents   []string        `json:"added_components,omitempty"`
+    RemovedComponents []string        `json:"removed_components,omitempty"`
+    Components        []ComponentDiff `json:"components,omitempty"`
+}
+
+// ComponentDiff lists the YAML-level changes for a component present in both versions.
+type ComponentDiff struct {
+    ID                 string        `json:"id"` // <type>/<name>, e.g. exporter/otlp
+    AddedKeys          []string      `json:"added_keys,omitempty"`
+    RemovedKeys        []string      `json:"removed_keys,omitempty"`
+    RenamedKeys        []KeyRename   `json:"renamed_keys,omitempty"`
+    Changes            []FieldChange `json:"changes,omitempty"`
+    AddedConstraints   []Constraint  `json:"added_constraints,omitempty"`
+    DroppedConstraints []Constraint  `json:"dropped_constraints,omitempty"`
+}
+
+type KeyRename struct {
+    From string `json:"from"`
+    To   string `json:"to"`
+}
+
+// FieldChange records a single attribute change on a key present in both versions.
+type FieldChange struct {
+    Key       string      `json:"key"`
+    Attribute string      `json:"attribute"` // default, enum_values, required, type, deprecated, replacement
+    Old       interface{} `json:"old,omitempty"`
+    New       interface{} `json:"new,omitempty"`
+}
+
+func (c ComponentDiff) empty() bool {
+    return len(c.AddedKeys) == 0 && len(c.RemovedKeys) == 0 && len(c.RenamedKeys) == 0 &&
+        len(c.Changes) == 0 && len(c.AddedConstraints) == 0 && len(c.DroppedConstraints) == 0
+}
+
+// runDiff implements: diff [--format=text|json] <old.json> <new.json>
+func runDiff(args []string) int {
+    fs := flag.NewFlagSet("diff", flag.ExitOnError)
+    format := fs.String("format", "text", "Output format: text or json")
+    fs.Usage = func() {
+        fmt.Fprintln(os.Stderr, "Usage: go run . diff [--format=text|json] <old configs.json> <new configs.json>")
+        fmt.Fprintln(os.Stderr, "Versions stored in components.db can be exported first with: go run build_database.go --output=components.db --export=<version>")
+        fs.PrintDefaults()
+    }
+    _ = fs.Parse(args)
+    if fs.NArg() != 2 {
+        fs.Usage()
+        return 2
+    }
+    if *format != "text" && *format != "json" {
+        fmt.Fprintf(os.Stderr, "diff: unknown format %q\n", *format)
+        return 2
+    }
+    oldData, err := loadExtractedData(fs.Arg(0))
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "diff: %v\n", err)
+        return 1
+    }
+    newData, err := loadExtractedData(fs.Arg(1))
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "diff: %v\n", err)
+        return 1
+    }
+    d := diffExtractedData(oldData, newData)
+    switch *format {
+    case "json":
+        data, err := json.MarshalIndent(d, "", "  ")
+        if err != nil {
+            fmt.Fprintf(os.Stderr, "diff: %v\n", err)
+            return 1
+        }
+        fmt.Println(string(data))
+    default:
+        writeDiffText(os.Stdout, d)
+    }
+    return 0
+}
+
+func componentKey(c *Component) string { return c.Type + "/" + c.Name }
+
+// fieldKey is the dotted YAML path of a field, falling back to its Go name when no tokens were recorded.
+func fieldKey(f *ConfigField) string {
+    if len(f.PathTokens) > 0 { return strings.Join(f.PathTokens, ".") }
+    if f.MapStructure != "" { return f.MapStructure }
+    return f.Name
+}
+
+func constraintKey(c Constraint) string {
+    keys := make([]string, 0, len(c.KeyTokens))
+    for _, k := range c.KeyTokens { keys = append(keys, strings.Join(k, ".")) }
+    sort.Strings(keys)
+    return c.Kind + ":" + strings.Join(keys, "|")
+}
+
+func diffExtractedData(oldData, newData *ExtractedData) SchemaDiff {
+    d := SchemaDiff{From: oldData.Version, To: newData.Version}
+    oldComps := map[string]*Component{}
+    for i := range oldData.Components { oldComps[componentKey(&oldData.Components[i])] = &oldData.Components[i] }
+    newComps := map[string]*Component{}
+    for i := range newData.Components { newComps[componentKey(&newData.Components[i])] = &newData.Components[i] }
+
+    for k := range newComps {
+        if _, ok := oldComps[k]; !ok { d.AddedComponents = append(d.AddedComponents, k) }
+    }
+    for k := range oldComps {
+        if _, ok := newComps[k]; !ok { d.RemovedComponents = append(d.RemovedComponents, k) }
+    }
+    sort.Strings(d.AddedComponents)
+    sort.Strings(d.RemovedComponents)
+
+    shared := make([]string, 0, len(newComps))
+    for k := range newComps {
+        if _, ok := oldComps[k]; ok { shared = append(shared, k) }
+    }
+    sort.Strings(shared)
+    for _, k := range shared {
+        if cd := diffComponent(oldComps[k], newComps[k]); !cd.empty() {
+            d.Components = append(d.Components, cd)
+        }
+    }
+    // The service section is compared like a component when both sides extracted it
+    if oldData.Service != nil && newData.Service != nil {
+        if cd := diffComponent(oldData.Service, newData.Service); !cd.empty() { d.Components = append(d.Components, cd) }
+    }
+    return d
+}
+
+func diffComponent(oldC, newC *Component) ComponentDiff {
+    cd := ComponentDiff{ID: componentKey(newC)}
+    oldFields := map[string]*ConfigField{}
+    for i := range oldC.Config.Fields { oldFields[fieldKey(&oldC.Config.Fields[i])] = &oldC.Config.Fields[i] }
+    newFields := map[string]*ConfigField{}
+    for i := range newC.Config.Fields { newFields[fieldKey(&newC.Config.Fields[i])] = &newC.Config.Fields[i] }
+
+    var added, removed []string
+    for k := range newFields {
+        if _, ok := oldFields[k]; !ok { added = append(added, k) }
+    }
+    for k := range oldFields {
+        if _, ok := newFields[k]; !ok { removed = append(removed, k) }
+    }
+    sort.Strings(added)
+    sort.Strings(removed)
+
+    // Pair removed/added keys that are the same Go field under a new YAML path.
+    renamedTo := map[string]bool{}
+    for _, r := range removed {
+        var match string
+        // A deprecated key whose announced replacement appears is a rename
+        if rp := oldFields[r].Replacement; rp != "" && newFields[rp] != nil && !renamedTo[rp] && containsToken(added, rp) { match = rp }
+        for _, a := range added {
+            if match != "" { break }
+            if renamedTo[a] { continue }
+            if isLikelyRename(oldFields[r], newFields[a]) { match = a; break }
+        }
+        if match == "" {
+            cd.RemovedKeys = append(cd.RemovedKeys, r)
+            continue
+        }
+        renamedTo[match] = true
+        cd.RenamedKeys = append(cd.RenamedKeys, KeyRename{From: r, To: match})
+    }
+    for _, a := range added {
+        if !renamedTo[a] { cd.AddedKeys = append(cd.AddedKeys, a) }
+    }
+
+    // Attribute changes on keys present in both versions
+    common := make([]string, 0, len(newFields))
+    for k := range newFields {
+        if _, ok := oldFields[k]; ok { common = append(common, k) }
+    }
+    sort.Strings(common)
+    for _, k := range common {
+        cd.Changes = append(cd.Changes, diffField(k, oldFields[k], newFields[k])...)
+    }
+    for _, rn := range cd.RenamedKeys {
+        for _, ch := range diffField(rn.To, oldFields[rn.From], newFields[rn.To]) {
+            // The rename itself completes a deprecation
+            if ch.Attribute == "deprecated" || ch.Attribute == "replacement" { continue }
+            cd.Changes = append(cd.Changes, ch)
+        }
+    }
+
+    oldCons := map[string]Constraint{}
+    for _, c := range oldC.Constraints { oldCons[constraintKey(c)] = c }
+    newCons := map[string]Constraint{}
+    for _, c := range newC.Constraints { newCons[constraintKey(c)] = c }
+    for _, k := range sortedKeys(newCons) {
+        if _, ok := oldCons[k]; !ok { cd.AddedConstraints = append(cd.AddedConstraints, newCons[k]) }
+    }
+    for _, k := range sortedKeys(oldCons) {
+        if _, ok := newCons[k]; !ok { cd.DroppedConstraints = append(cd.DroppedConstraints, oldCons[k]) }
+    }
+    return cd
+}
+
+// isLikelyRename reports whether a removed and an added field of the same kind describe the
+// same setting: two of the same Go field name, the same non-empty description and the same
+// parent path. A Go name alone is not enough; unrelated Enabled or Endpoint fields share it.
+func isLikelyRename(oldF, newF *ConfigField) bool {
+    if oldF.Type != newF.Type { return false }
+    sameName := oldF.Name != "" && oldF.Name == newF.Name
+    sameDesc := oldF.Description != "" && oldF.Description == newF.Description
+    op, np := oldF.PathTokens, newF.PathTokens
+    sameParent := len(op) > 0 && len(op) == len(np) && reflect.DeepEqual(op[:len(op)-1], np[:len(np)-1])
+    return (sameName && (sameDesc || sameParent)) || (sameDesc && sameParent)
+}
+
+func diffField(key string, oldF, newF *ConfigField) []FieldChange {
+    var out []FieldChange
+    if oldF.Type != newF.Type {
+        out = append(out, FieldChange{Key: key, Attribute: "type", Old: oldF.Type, New: newF.Type})
+    }
+    if !sameJSONValue(oldF.Default, newF.Default) {
+        out = append(out, FieldChange{Key: key, Attribute: "default", Old: oldF.Default, New: newF.Default})
+    }
+    if !sameSet(oldF.EnumValues, newF.EnumValues) {
+        out = append(out, FieldChange{Key: key, Attribute: "enum_values", Old: oldF.EnumValues, New: newF.EnumValues})
+    }
+    if oldF.Required != newF.Required {
+        out = append(out, FieldChange{Key: key, Attribute: "required", Old: oldF.Required, New: newF.Required})
+    }
+    if oldF.Deprecated != newF.Deprecated {
+        out = append(out, FieldChange{Key: key, Attribute: "deprecated", Old: oldF.Deprecated, New: newF.Deprecated})
+    }
+    if oldF.Replacement != newF.Replacement {
+        out = append(out, FieldChange{Key: key, Attribute: "replacement", Old: oldF.Replacement, New: newF.Replacement})
+    }
+    return out
+}
+
+// sameJSONValue compares defaults by their JSON encoding so int64 vs float64 decodings match.
+func sameJSONValue(a, b interface{}) bool {
+    ja, _ := json.Marshal(a)
+    jb, _ := json.Marshal(b)
+    return string(ja) == string(jb)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+    keys := make([]string, 0, len(m))
+    for k := range m { keys = append(keys, k) }
+    sort.Strings(keys)
+    return keys
+}
+
+func formatDiffValue(v interface{}) string {
+    if v == nil { return "(none)" }
+    data, err := json.Marshal(v)
+    if err != nil { return fmt.Sprint(v) }
+    return string(data)
+}
+
+func formatConstraint(c Constraint) string {
+    keys := make([]string, 0, len(c.KeyTokens))
+    for _, k := range c.KeyTokens { keys = append(keys, strings.Join(k, ".")) }
+    return fmt.Sprintf("%s(%s)", c.Kind, strings.Join(keys, ", "))
+}
+
+func writeDiffText(w io.Writer, d SchemaDiff) {
+    fmt.Fprintf(w, "Schema changes %s -> %s\n", d.From, d.To)
+    if len(d.AddedComponents) == 0 && len(d.RemovedComponents) == 0 && len(d.Components) == 0 {
+        fmt.Fprintln(w, "\nNo changes.")
+        return
+    }
+    if len(d.AddedComponents) > 0 {
+        fmt.Fprintf(w, "\nAdded components (%d):\n", len(d.AddedComponents))
+        for _, c := range d.AddedComponents { fmt.Fprintf(w, "  + %s\n", c) }
+    }
+    if len(d.RemovedComponents) > 0 {
+        fmt.Fprintf(w, "\nRemoved components (%d):\n", len(d.RemovedComponents))
+        for _, c := range d.RemovedComponents { fmt.Fprintf(w, "  - %s\n", c) }
+    }
+    for _, cd := range d.Components {
+        fmt.Fprintf(w, "\n%s\n", cd.ID)
+        for _, k := range cd.AddedKeys { fmt.Fprintf(w, "  + %s\n", k) }
+        for _, k := range cd.RemovedKeys { fmt.Fprintf(w, "  - %s\n", k) }
+        for _, r := range cd.RenamedKeys { fmt.Fprintf(w, "  ~ %s -> %s (renamed)\n", r.From, r.To) }
+        for _, ch := range cd.Changes {
+            fmt.Fprintf(w, "  * %s: %s %s -> %s\n", ch.Key, ch.Attribute, formatDiffValue(ch.Old), formatDiffValue(ch.New))
+        }
+        for _, c := range cd.AddedConstraints { fmt.Fprintf(w, "  + constraint %s\n", formatConstraint(c)) }
+        for _, c := range cd.DroppedConstraints { fmt.Fprintf(w, "  - constraint %s\n", formatConstraint(c)) }
+    }
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+    "bytes"
+    "strings"
+    "testing"
+)
+
+// TestDiffExtractedData diffs two hand-written schema versions and checks the text changelog:
+// added and removed components, keys and constraints, a renamed key and attribute changes.
+func TestDiffExtractedData(t *testing.T) {
+    oldData := &ExtractedData{Version: "v1", Components: []Component{
+        {Type: "receiver", Name: "gone"},
+        {Type: "exporter", Name: "otlp", Config: ConfigSchema{Fields: []ConfigField{
+            {Name: "Endpoint", Type: "string", PathTokens: []string{"endpoint"}, Required: true},
+            {Name: "Compression", Type: "enum", PathTokens: []string{"compression"}, Default: "gzip", EnumValues: []string{"gzip", "none"}},
+            {Name: "InsecureSkip", Type: "bool", PathTokens: []string{"tls", "insecure_skip"}},
+            {Name: "Timeout", Type: "int", PathTokens: []string{"timeout"}},
+            {Name: "Legacy", Type: "string", PathTokens: []string{"legacy"}},
+        }}, Constraints: []Constraint{{Kind: "oneOf", KeyTokens: [][]string{{"endpoint"}, {"url"}}}}},
+        {Type: "processor", Name: "batch", Config: ConfigSchema{Fields: []ConfigField{
+            {Name: "Timeout", Type: "duration", PathTokens: []string{"timeout"}, Default: "200ms"},
+        }}},
+    }}
+    newData := &ExtractedData{Version: "v2", Components: []Component{
+        {Type: "exporter", Name: "otlp", Config: ConfigSchema{Fields: []ConfigField{
+            {Name: "Endpoint", Type: "string", PathTokens: []string{"endpoint"}},
+            {Name: "Compression", Type: "enum", PathTokens: []string{"compression"}, Default: "zstd", EnumValues: []string{"gzip", "none", "zstd"}},
+            {Name: "InsecureSkip", Type: "bool", PathTokens: []string{"tls", "insecure_skip_verify"}},
+            {Name: "Timeout", Type: "duration", PathTokens: []string{"timeout"}},
+            {Name: "Headers", Type: "stringMap", PathTokens: []string{"headers"}},
+        }}, Constraints: []Constraint{{Kind: "anyOf", KeyTokens: [][]string{{"endpoint"}, {"url"}}}}},
+        {Type: "processor", Name: "batch", Config: ConfigSchema{Fields: []ConfigField{
+            {Name: "Timeout", Type: "duration", PathTokens: []string{"timeout"}, Default: "200ms"},
+        }}},
+        {Type: "receiver", Name: "new"},
+    }}
+    var buf bytes.Buffer
+    writeDiffText(&buf, diffExtractedData(oldData, newData))
+    want := `Schema changes v1 -> v2
+
+Added components (1):
+  + receiver/new
+
+Removed components (1):
+  - receiver/gone
+
+exporter/otlp
+  + headers
+  - legacy
+  ~ tls.insecure_skip -> tls.insecure_skip_verify (renamed)
+  * compression: default "gzip" -> "zstd"
+  * compression: enum_values ["gzip","none"] -> ["gzip","none","zstd"]
+  * endpoint: required true -> false
+  * timeout: type "int" -> "duration"
+  + constraint anyOf(endpoint, url)
+  - constraint oneOf(endpoint, url)
+`
+    if got := buf.String(); got != want { t.Errorf("diff:\n%s\nwant:\n%s", got, want) }
+}
+
+// TestDiffDeprecations reports keys becoming deprecated and pairs a removed deprecated key
+// with its announced replacement even when the two look nothing alike.
+func TestDiffDeprecations(t *testing.T) {
+    oldData := &ExtractedData{Version: "v1", Components: []Component{
+        {Type: "processor", Name: "batch", Config: ConfigSchema{Fields: []ConfigField{
+            {Name: "MaxSize", Type: "int", PathTokens: []string{"send_batch_max"}, Deprecated: true, Replacement: "limits.max_items"},
+            {Name: "Timeout", Type: "duration", PathTokens: []string{"timeout"}},
+        }}},
+    }}
+    newData := &ExtractedData{Version: "v2", Components: []Component{
+        {Type: "processor", Name: "batch", Config: ConfigSchema{Fields: []ConfigField{
+            {Name: "MaxItems", Type: "int", PathTokens: []string{"limits", "max_items"}},
+            {Name: "Timeout", Type: "duration", PathTokens: []string{"timeout"}, Deprecated: true, Replacement: "flush_interval"},
+        }}},
+    }}
+    var buf bytes.Buffer
+    writeDiffText(&buf, diffExtractedData(oldData, newData))
+    want := `Schema changes v1 -> v2
+
+processor/batch
+  ~ send_batch_max -> limits.max_items (renamed)
+  * timeout: deprecated false -> true
+  * timeout: replacement "" -> "flush_interval"
+`
+    if got := buf.String(); got != want { t.Errorf("diff:\n%s\nwant:\n%s", got, want) }
+}
+
+// TestDiffRenames pairs removed and added keys only when they are the same setting.
+func TestDiffRenames(t *testing.T) {
+    field := func(name, typ, desc string, path ...string) ConfigField {
+        return ConfigField{Name: name, Type: typ, Description: desc, PathTokens: path}
+    }
+    oldC := &Component{Type: "exporter", Name: "x", Config: ConfigSchema{Fields: []ConfigField{
+        field("InsecureSkipVerify", "bool", "", "tls", "insecure_skip"),
+        field("Compression", "string", "Compression codec.", "compression"),
+        field("Enabled", "bool", "Enabled turns retries on.", "retry", "enabled"),
+        field("Endpoint", "string", "Endpoint of the backend.", "endpoint"),
+    }}}
+    newC := &Component{Type: "exporter", Name: "x", Config: ConfigSchema{Fields: []ConfigField{
+        field("InsecureSkipVerify", "bool", "", "tls", "insecure_skip_verify"),
+        field("Compression", "string", "Compression codec.", "client", "compression"),
+        field("Enabled", "bool", "Enabled turns the queue on.", "sending_queue", "enabled"),
+        field("Endpoint", "string", "Endpoint of the health check.", "health", "endpoint"),
+    }}}
+    cd := diffComponent(oldC, newC)
+    var renames []string
+    for _, r := range cd.RenamedKeys { renames = append(renames, r.From+" -> "+r.To) }
+    if got, want := strings.Join(renames, ", "), "compression -> client.compression, tls.insecure_skip -> tls.insecure_skip_verify"; got != want {
+        t.Errorf("renames = %s, want %s", got, want)
+    }
+    if got, want := strings.Join(cd.RemovedKeys, ", "), "endpoint, retry.enabled"; got != want { t.Errorf("removed = %s, want %s", got, want) }
+    if got, want := strings.Join(cd.AddedKeys, ", "), "health.endpoint, sending_queue.enabled"; got != want { t.Errorf("added = %s, want %s", got, want) }
+}
+
+// TestRunDiffUsage returns 2 for usage errors, like the other subcommands.
+func TestRunDiffUsage(t *testing.T) {
+    for _, args := range [][]string{{"old.json"}, {"--format=yaml", "old.json", "new.json"}} {
+        if got := runDiff(args); got != 2 { t.Errorf("runDiff(%q) = %d, want 2", args, got) }
+    }
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+    "flag"
+    "fmt"
+    "os"
+    "sort"
+    "strings"
+    "text/tabwriter"
+
+    "gopkg.in/yaml.v3"
+)
+
+// structEmbedding is a Go config struct exposed by a component at a YAML path.
+type structEmbedding struct {
+    Path   []string
+    Struct string // "<import path>.<Type>"
+}
+
+// runEmbedders implements:
+//   embedders --schema=configs.json configtls.ClientConfig
+//   embedders --schema=configs.json configtls <collector.yaml>...
+// It lists the components exposing fields of a Go config struct (or of any struct of a
+// package) and the path they sit at; with config files, only the components configured
+// there, so a change to a shared config package maps to the affected config entries.
+func runEmbedders(args []string) int {
+    fs := flag.NewFlagSet("embedders", flag.ExitOnError)
+    schemaPath := fs.String("schema", "", "Extracted configs JSON")
+    fs.Usage = func() {
+        fmt.Fprintln(os.Stderr, "Usage: go run . embedders --schema=configs.json <type|package> [collector.yaml...]")
+        fmt.Fprintln(os.Stderr, "  type: configtls.ClientConfig, ClientConfig or a full import path plus type; package: configtls")
+        fs.PrintDefaults()
+    }
+    _ = fs.Parse(args)
+    if *schemaPath == "" || fs.NArg() == 0 {
+        fs.Usage()
+        return 2
+    }
+    data, err := loadExtractedData(*schemaPath)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "embedders: %v\n", err)
+        return 1
+    }
+    target := fs.Arg(0)
+    found := false
+    if fs.NArg() == 1 {
+        comps := make([]*Component, 0, len(data.Components)+1)
+        for i := range data.Components { comps = append(comps, &data.Components[i]) }
+        if data.Service != nil { comps = append(comps, data.Service) }
+        sort.SliceStable(comps, func(i, j int) bool { return componentKey(comps[i]) < componentKey(comps[j]) })
+        tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+        for _, c := range comps {
+            for _, e := range structEmbeddings(c, target) {
+                fmt.Fprintf(tw, "%s\t%s\t%s\n", componentKey(c), embeddingPath(e.Path), e.Struct)
+                found = true
+            }
+        }
+        _ = tw.Flush()
+    } else {
+        idx := newSchemaIndex(data)
+        for _, path := range fs.Args()[1:] {
+            content, err := os.ReadFile(path)
+            if err != nil {
+                fmt.Fprintf(os.Stderr, "embedders: %v\n", err)
+                return 1
+            }
+            root, err := parseConfigYAML(content)
+            if err != nil {
+                fmt.Fprintf(os.Stderr, "embedders: %s: %v\n", path, err)
+                return 1
+            }
+            report := func(line int, entry string, c *Component) {
+                for _, e := range structEmbeddings(c, target) {
+                    fmt.Printf("%s:%d: %s: %s (%s)\n", path, line, entry, embeddingPath(e.Path), e.Struct)
+                    found = true
+                }
+            }
+            for _, sec := range componentSections {
+                _, entries := mappingValue(root, sec.section)
+                if entries == nil || entries.Kind != yaml.MappingNode { continue }
+                for i := 0; i+1 < len(entries.Content); i += 2 {
+                    id := entries.Content[i]
+                    if c := idx.component(sec.kind, id.Value); c != nil { report(id.Line, sec.section+"::"+id.Value, c) }
+                }
+            }
+            if key, _ := mappingValue(root, "service"); key != nil && data.Service != nil { report(key.Line, "service", data.Service) }
+        }
+    }
+    if !found {
+        fmt.Fprintf(os.Stderr, "embedders: nothing exposes %s\n", target)
+        return 1
+    }
+    return 0
+}
+
+// structEmbeddings lists where c exposes structs matching target: the structs declaring
+// its fields and the shared structs embedding those.
+func structEmbeddings(c *Component, target string) []structEmbedding {
+    seen := map[string]bool{}
+    var out []structEmbedding
+    add := func(key string, path []string) {
+        if key == "" || !structMatches(key, target) { return }
+        id := key + "\x00" + strings.Join(path, "\x00")
+        if seen[id] { return }
+        seen[id] = true
+        out = append(out, structEmbedding{Path: path, Struct: key})
+    }
+    for _, f := range c.Config.Fields {
+        add(f.Shared, f.SharedPath)
+        add(f.DeclaredIn, f.DeclaredPath)
+    }
+    sort.SliceStable(out, func(i, j int) bool {
+        pi, pj := strings.Join(out[i].Path, "."), strings.Join(out[j].Path, ".")
+        if pi != pj { return pi < pj }
+        return out[i].Struct < out[j].Struct
+    })
+    return out
+}
+
+// structMatches reports whether a "<import path>.<Type>" key is target: the full key, a
+// suffix after a path separator (configtls.ClientConfig), a bare type name, or its package.
+func structMatches(key, target string) bool {
+    dot := strings.LastIndex(key, ".")
+    if dot < 0 { return false }
+    pkg := key[:dot]
+    for _, cand := range []string{key, pkg} {
+        if cand == target || strings.HasSuffix(cand, "/"+target) { return true }
+    }
+    return key[dot+1:] == target
+}
+
+func embeddingPath(path []string) string {
+    if len(path) == 0 { return "(top level)" }
+    return strings.Join(path, ".")
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+    "strings"
+    "testing"
+)
+
+// TestStructEmbeddings looks up fixture components by the shared config structs they expose.
+func TestStructEmbeddings(t *testing.T) {
+    _, components := fixtureComponents(t)
+    var got []string
+    for _, c := range components {
+        c := c
+        for _, e := range structEmbeddings(&c, "configretry") { got = append(got, componentKey(&c)+" "+embeddingPath(e.Path)) }
+        for _, e := range structEmbeddings(&c, "confighttp.ClientConfig") { got = append(got, componentKey(&c)+" "+embeddingPath(e.Path)) }
+    }
+    want := "exporter/alias client, exporter/retry reconnect, exporter/retry retry_on_failure, exporter/retry (top level)"
+    if strings.Join(got, ", ") != want { t.Errorf("embeddings = %v, want %s", got, want) }
+}
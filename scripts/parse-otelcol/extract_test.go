@@ -0,0 +1,210 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+// TestExtractGolden extracts every fixture component and compares it with
+// testdata/golden/<type>_<dir>.json. Run `go test -run TestExtractGolden -update`
+// after an intended extractor change and review the golden diff.
+func TestExtractGolden(t *testing.T) {
+    runGoldenExtraction(t, false, filepath.Join("testdata", "golden"))
+}
+
+// TestExtractGoldenTyped runs the same fixtures with --typed (go/types classification).
+func TestExtractGoldenTyped(t *testing.T) {
+    runGoldenExtraction(t, true, filepath.Join("testdata", "golden", "typed"))
+}
+
+func runGoldenExtraction(t *testing.T, typedMode bool, goldenDir string) {
+    // Packages are cached globally and carry type info only when loaded in typed mode
+    saved := *typed
+    *typed = typedMode
+    defer func() { *typed = saved }()
+
+    root := fixturePackages(t)
+    tasks := componentTasks(root, false)
+    if len(tasks) == 0 { t.Fatalf("no fixture components under %s", fixtureRoot) }
+    for _, task := range tasks {
+        task := task
+        t.Run(task.typ+"/"+task.name, func(t *testing.T) {
+            c := extractComponent(task.componentPath, task.name, task.typ, task.isContrib)
+            if c == nil { t.Fatal("extraction failed") }
+            got, err := json.MarshalIndent(c, "", "  ")
+            if err != nil { t.Fatal(err) }
+            got = append(got, '\n')
+            checkGolden(t, filepath.Join(goldenDir, task.typ+"_"+task.name+".json"), got)
+        })
+    }
+}
+
+// TestExtractSignals reads the signals, connector pairs and stability levels a factory declares.
+func TestExtractSignals(t *testing.T) {
+    for _, tt := range []struct {
+        name, factory, signals, pairs, stability string
+    }{
+        {"receiver", `receiver.NewFactory(typ, createDefaultConfig, receiver.WithTraces(createTraces, component.StabilityLevelBeta), receiver.WithLogs(createLogs, logsStability))`, `["logs","traces"]`, `null`, `{"logs":"alpha","traces":"beta"}`},
+        {"connector", `connector.NewFactory(typ, createDefaultConfig, connector.WithTracesToMetrics(createTM, component.StabilityLevelAlpha), connector.WithLogsToMetrics(createLM, component.StabilityLevelDevelopment), connector.WithTracesToMetrics(createTM, component.StabilityLevelAlpha))`, `[]`, `[{"from":"logs","to":"metrics"},{"from":"traces","to":"metrics"}]`, `{"logs_to_metrics":"development","traces_to_metrics":"alpha"}`},
+    } {
+        t.Run(tt.name, func(t *testing.T) {
+            dir := t.TempDir()
+            src := "package example\n\nconst logsStability = component.StabilityLevelAlpha\n\nfunc NewFactory() Factory {\n    return " + tt.factory + "\n}\n"
+            if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example\n\ngo 1.25\n"), 0644); err != nil { t.Fatal(err) }
+            if err := os.WriteFile(filepath.Join(dir, "factory.go"), []byte(src), 0644); err != nil { t.Fatal(err) }
+            signals, pairs, stability := extractSignals(dir)
+            gotSignals, _ := json.Marshal(signals)
+            gotPairs, _ := json.Marshal(pairs)
+            gotStability, _ := json.Marshal(stability)
+            if string(gotSignals) != tt.signals || string(gotPairs) != tt.pairs { t.Errorf("signals = %s, pairs = %s; want %s, %s", gotSignals, gotPairs, tt.signals, tt.pairs) }
+            if string(gotStability) != tt.stability { t.Errorf("stability = %s, want %s", gotStability, tt.stability) }
+        })
+    }
+}
+
+// TestComponentMetadataStability inverts metadata.yaml status.stability into signal -> level.
+func TestComponentMetadataStability(t *testing.T) {
+    dir := t.TempDir()
+    md := "type: example\nstatus:\n  class: receiver\n  stability:\n    beta: [traces, metrics]\n    alpha: [logs]\n"
+    if err := os.WriteFile(filepath.Join(dir, "metadata.yaml"), []byte(md), 0644); err != nil { t.Fatal(err) }
+    got, _ := json.Marshal(readComponentMetadata(dir).stabilityBySignal())
+    if want := `{"logs":"alpha","metrics":"beta","traces":"beta"}`; string(got) != want { t.Errorf("stability = %s, want %s", got, want) }
+    if got := readComponentMetadata(t.TempDir()).stabilityBySignal(); len(got) != 0 { t.Errorf("stability without metadata.yaml = %v, want empty", got) }
+}
+
+// TestApplyComponentMetadata copies description, distributions, codeowners, emitted metrics
+// and resource attributes from metadata.yaml; factory-declared stability wins.
+func TestApplyComponentMetadata(t *testing.T) {
+    dir := t.TempDir()
+    md := `type: example
+description: Example receiver.
+status:
+  class: receiver
+  stability:
+    beta: [metrics]
+    alpha: [logs]
+  distributions: [contrib, core]
+  codeowners:
+    active: [alice]
+attributes:
+  state:
+    description: Connection state.
+    type: string
+    name_override: conn.state
+    enum: [open, closed]
+resource_attributes:
+  host.name:
+    description: Host name.
+    type: string
+    enabled: true
+metrics:
+  example.connections:
+    enabled: true
+    description: Open connections.
+    unit: "{connections}"
+    sum: {}
+    attributes: [state]
+  example.bytes:
+    enabled: false
+    unit: By
+    gauge: {}
+`
+    if err := os.WriteFile(filepath.Join(dir, "metadata.yaml"), []byte(md), 0644); err != nil { t.Fatal(err) }
+    c := &Component{Name: "example", Type: "receiver", Stability: map[string]string{"metrics": "stable"}}
+    applyComponentMetadata(c, readComponentMetadata(dir))
+    got, _ := json.Marshal(c)
+    want := `{"name":"example","type":"receiver","description":"Example receiver.","config":{"fields":null,"examples":null},"constraints":null,` +
+        `"stability":{"logs":"alpha","metrics":"stable"},"distributions":["contrib","core"],"codeowners":["alice"],` +
+        `"metrics":[{"name":"example.bytes","unit":"By","type":"gauge","enabled":false},` +
+        `{"name":"example.connections","description":"Open connections.","unit":"{connections}","type":"sum","enabled":true,"attributes":[{"name":"conn.state","description":"Connection state.","type":"string","enum":["open","closed"]}]}],` +
+        `"resource_attributes":[{"name":"host.name","description":"Host name.","type":"string","enabled":true}]}`
+    if string(got) != want { t.Errorf("component:\n%s\nwant:\n%s", got, want) }
+}
+
+// TestExpandMdatagenConfig expands a squashed mdatagen MetricsBuilderConfig into one
+// enabled toggle per metadata.yaml metric and resource attribute.
+func TestExpandMdatagenConfig(t *testing.T) {
+    dir := t.TempDir()
+    writeTree(t, dir, map[string]string{
+        "go.mod": "module example\n\ngo 1.25\n",
+        "metadata.yaml": `type: example
+status:
+  class: receiver
+resource_attributes:
+  host.name:
+    description: Host name.
+    type: string
+    enabled: true
+metrics:
+  example.bytes:
+    enabled: true
+    description: Bytes received.
+    unit: By
+    sum: {}
+  example.errors:
+    enabled: false
+    description: Receive errors.
+    unit: "{errors}"
+    sum: {}
+`,
+        "config.go": `package example
+
+import "example/internal/metadata"
+
+// Config for the example receiver.
+type Config struct {
+    metadata.MetricsBuilderConfig ` + "`mapstructure:\",squash\"`" + `
+    // Verbose enables debug logging.
+    Verbose bool ` + "`mapstructure:\"verbose\"`" + `
+}
+`,
+        "factory.go": `package example
+
+func createDefaultConfig() component.Config {
+    return &Config{}
+}
+`,
+        "internal/metadata/generated_config.go": `package metadata
+
+type MetricConfig struct {
+    Enabled bool ` + "`mapstructure:\"enabled\"`" + `
+}
+
+type MetricsConfig struct {
+    ExampleBytes  MetricConfig ` + "`mapstructure:\"example.bytes\"`" + `
+    ExampleErrors MetricConfig ` + "`mapstructure:\"example.errors\"`" + `
+}
+
+type ResourceAttributeConfig struct {
+    Enabled bool ` + "`mapstructure:\"enabled\"`" + `
+}
+
+type ResourceAttributesConfig struct {
+    HostName ResourceAttributeConfig ` + "`mapstructure:\"host.name\"`" + `
+}
+
+type MetricsBuilderConfig struct {
+    Metrics            MetricsConfig            ` + "`mapstructure:\"metrics\"`" + `
+    ResourceAttributes ResourceAttributesConfig ` + "`mapstructure:\"resource_attributes\"`" + `
+}
+`,
+    })
+    c := extractComponent(dir, "example", "receiver", false)
+    if c == nil { t.Fatal("extractComponent returned nil") }
+    var got []string
+    for _, f := range c.Config.Fields {
+        tokens, _ := json.Marshal(f.PathTokens)
+        got = append(got, fmt.Sprintf("%s %s default=%v %q", tokens, f.Type, f.Default, f.Description))
+    }
+    want := []string{
+        `["metrics","example.bytes","enabled"] bool default=true "Bytes received."`,
+        `["metrics","example.errors","enabled"] bool default=false "Receive errors."`,
+        `["resource_attributes","host.name","enabled"] bool default=true "Host name."`,
+        `["verbose"] bool default=<nil> "Verbose enables debug logging."`,
+    }
+    if strings.Join(got, "\n") != strings.Join(want, "\n") { t.Errorf("fields:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n")) }
+}
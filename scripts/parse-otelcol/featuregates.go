@@ -0,0 +1,249 @@
+package main
+
+import (
+    "bytes"
+    "go/ast"
+    "go/parser"
+    "go/token"
+    "os"
+    "path/filepath"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+// FeatureGate is a featuregate registry registration found in the collector sources.
+type FeatureGate struct {
+    ID           string   `json:"id"`
+    Stage        string   `json:"stage"` // alpha, beta, stable, deprecated
+    Description  string   `json:"description,omitempty"`
+    ReferenceURL string   `json:"reference_url,omitempty"`
+    FromVersion  string   `json:"from_version,omitempty"`
+    ToVersion    string   `json:"to_version,omitempty"`
+    Package      string   `json:"package"`              // import path of the declaring package
+    Components   []string `json:"components,omitempty"` // <type>/<name> of components declaring or checking the gate
+    varName      string   // package-level variable holding the *featuregate.Gate, if any
+    dir          string
+}
+
+// featureGateOptions maps featuregate.WithRegister* options to the field they set.
+var featureGateOptions = map[string]func(g *FeatureGate, v string){
+    "WithRegisterDescription":  func(g *FeatureGate, v string) { g.Description = v },
+    "WithRegisterReferenceURL": func(g *FeatureGate, v string) { g.ReferenceURL = v },
+    "WithRegisterFromVersion":  func(g *FeatureGate, v string) { g.FromVersion = v },
+    "WithRegisterToVersion":    func(g *FeatureGate, v string) { g.ToVersion = v },
+}
+
+// extractFeatureGates finds every Register/MustRegister call in the given repos and links
+// each gate to the extracted components whose package tree declares it or references its variable.
+func extractFeatureGates(components []Component, repos ...string) []FeatureGate {
+    var gates []FeatureGate
+    for _, repo := range repos {
+        _ = filepath.WalkDir(repo, func(path string, d os.DirEntry, err error) error {
+            if err != nil { return nil }
+            if d.IsDir() {
+                if path != repo && skipCacheDir(d.Name()) { return filepath.SkipDir }
+                gates = append(gates, featureGatesInDir(path)...)
+            }
+            return nil
+        })
+    }
+    if len(gates) == 0 { return nil }
+
+    byVar := map[string][]int{} // <import path>.<var> -> gate indexes
+    for i := range gates {
+        if gates[i].varName != "" { byVar[gates[i].Package+"."+gates[i].varName] = append(byVar[gates[i].Package+"."+gates[i].varName], i) }
+    }
+    for i := range components {
+        c := &components[i]
+        dir := componentDirFromModule(c.Module, repos)
+        if dir == "" { continue }
+        linked := map[int]bool{}
+        for gi := range gates {
+            if gates[gi].dir == dir || strings.HasPrefix(gates[gi].dir, dir+string(filepath.Separator)) { linked[gi] = true }
+        }
+        for _, ref := range packageTreeRefs(dir) {
+            for _, gi := range byVar[ref] { linked[gi] = true }
+        }
+        for gi := range linked { gates[gi].Components = append(gates[gi].Components, componentKey(c)) }
+    }
+    for i := range gates { sort.Strings(gates[i].Components) }
+    sort.SliceStable(gates, func(i, j int) bool { return gates[i].ID < gates[j].ID })
+    return gates
+}
+
+// componentDirFromModule maps a component import path back to its directory in one of the repos.
+func componentDirFromModule(module string, repos []string) string {
+    for _, repo := range repos {
+        _, mod := findGoModRoot(repo)
+        if mod == "" { continue }
+        if dir := modulePathDir(mod, repo, module); dir != "" { return dir }
+    }
+    return ""
+}
+
+// featureGatesInDir parses the non-test Go files of one package that mention featuregate.
+func featureGatesInDir(dir string) []FeatureGate {
+    entries, err := os.ReadDir(dir)
+    if err != nil { return nil }
+    sources := map[string][]byte{}
+    mentions := false
+    for _, e := range entries {
+        if e.IsDir() || !isSourceGoFile(e.Name()) { continue }
+        data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+        if err != nil { continue }
+        sources[e.Name()] = data
+        if bytes.Contains(data, []byte("featuregate.")) { mentions = true }
+    }
+    // Only packages importing featuregate are parsed (constants may live in sibling files)
+    if !mentions { return nil }
+    fset := token.NewFileSet()
+    var files []*ast.File
+    for _, name := range sortedKeys(sources) {
+        if f, err := parser.ParseFile(fset, name, sources[name], parser.SkipObjectResolution); err == nil { files = append(files, f) }
+    }
+    consts := stringConsts(files)
+    pkgPath := dirImportPath(dir)
+    var out []FeatureGate
+    for _, f := range files {
+        // Gates assigned to package-level variables can be linked to the code checking them
+        assigned := map[*ast.CallExpr]string{}
+        for _, decl := range f.Decls {
+            gd, ok := decl.(*ast.GenDecl)
+            if !ok || gd.Tok != token.VAR { continue }
+            for _, spec := range gd.Specs {
+                vs := spec.(*ast.ValueSpec)
+                for i, v := range vs.Values {
+                    if call, ok := v.(*ast.CallExpr); ok && i < len(vs.Names) { assigned[call] = vs.Names[i].Name }
+                }
+            }
+        }
+        ast.Inspect(f, func(n ast.Node) bool {
+            call, ok := n.(*ast.CallExpr)
+            if !ok { return true }
+            g, ok := featureGateFromCall(call, consts)
+            if !ok { return true }
+            g.Package, g.dir, g.varName = pkgPath, dir, assigned[call]
+            out = append(out, g)
+            return true
+        })
+    }
+    return out
+}
+
+// featureGateFromCall recognizes <registry>.MustRegister/Register("id", featuregate.StageX, opts...).
+func featureGateFromCall(call *ast.CallExpr, consts map[string]string) (FeatureGate, bool) {
+    sel, ok := call.Fun.(*ast.SelectorExpr)
+    if !ok || (sel.Sel.Name != "MustRegister" && sel.Sel.Name != "Register") || len(call.Args) < 2 { return FeatureGate{}, false }
+    stage, ok := call.Args[1].(*ast.SelectorExpr)
+    if !ok || !strings.HasPrefix(stage.Sel.Name, "Stage") { return FeatureGate{}, false }
+    id := stringExprValue(call.Args[0], consts)
+    if id == "" { return FeatureGate{}, false }
+    g := FeatureGate{ID: id, Stage: strings.ToLower(strings.TrimPrefix(stage.Sel.Name, "Stage"))}
+    for _, arg := range call.Args[2:] {
+        opt, ok := arg.(*ast.CallExpr)
+        if !ok || len(opt.Args) == 0 { continue }
+        name := ""
+        switch fn := opt.Fun.(type) {
+        case *ast.SelectorExpr:
+            name = fn.Sel.Name
+        case *ast.Ident:
+            name = fn.Name
+        }
+        if set := featureGateOptions[name]; set != nil { set(&g, stringExprValue(opt.Args[0], consts)) }
+    }
+    return g, true
+}
+
+// stringExprValue evaluates a string literal, a package string constant or a + concatenation of them.
+func stringExprValue(e ast.Expr, consts map[string]string) string {
+    switch t := e.(type) {
+    case *ast.BasicLit:
+        if t.Kind != token.STRING { return "" }
+        s, err := strconv.Unquote(t.Value)
+        if err != nil { return "" }
+        return s
+    case *ast.Ident:
+        return consts[t.Name]
+    case *ast.BinaryExpr:
+        if t.Op == token.ADD { return stringExprValue(t.X, consts) + stringExprValue(t.Y, consts) }
+    case *ast.ParenExpr:
+        return stringExprValue(t.X, consts)
+    }
+    return ""
+}
+
+// stringConsts collects package-level string constants (IDs and descriptions are often declared as consts).
+func stringConsts(files []*ast.File) map[string]string {
+    out := map[string]string{}
+    // Constants may refer to each other; a few passes resolve short chains
+    for pass := 0; pass < 3; pass++ {
+        for _, f := range files {
+            for _, decl := range f.Decls {
+                gd, ok := decl.(*ast.GenDecl)
+                if !ok || gd.Tok != token.CONST { continue }
+                for _, spec := range gd.Specs {
+                    vs := spec.(*ast.ValueSpec)
+                    for i, n := range vs.Names {
+                        if i >= len(vs.Values) || out[n.Name] != "" { continue }
+                        if s := stringExprValue(vs.Values[i], out); s != "" { out[n.Name] = s }
+                    }
+                }
+            }
+        }
+    }
+    return out
+}
+
+// dirImportPath derives a directory's import path from its governing go.mod.
+func dirImportPath(dir string) string {
+    root, mod := findGoModRoot(dir)
+    if mod == "" { return "" }
+    rel, err := filepath.Rel(root, dir)
+    if err != nil || rel == "." { return mod }
+    return mod + "/" + filepath.ToSlash(rel)
+}
+
+// packageTreeRefs lists the "<import path>.<name>" identifiers referenced by the non-test Go
+// files below dir: selectors on imported packages and bare identifiers of the file's own package.
+func packageTreeRefs(dir string) []string {
+    refs := map[string]bool{}
+    importPaths := map[string]string{}
+    fset := token.NewFileSet()
+    _ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+        if err != nil { return nil }
+        if d.IsDir() {
+            if path != dir && skipCacheDir(d.Name()) { return filepath.SkipDir }
+            return nil
+        }
+        if !isSourceGoFile(d.Name()) { return nil }
+        f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+        if err != nil { return nil }
+        own, ok := importPaths[filepath.Dir(path)]
+        if !ok {
+            own = dirImportPath(filepath.Dir(path))
+            importPaths[filepath.Dir(path)] = own
+        }
+        imports := map[string]string{}
+        for _, is := range f.Imports {
+            p, _ := strconv.Unquote(is.Path.Value)
+            alias := p[strings.LastIndex(p, "/")+1:]
+            if is.Name != nil { alias = is.Name.Name }
+            imports[alias] = p
+        }
+        ast.Inspect(f, func(n ast.Node) bool {
+            switch t := n.(type) {
+            case *ast.SelectorExpr:
+                if x, ok := t.X.(*ast.Ident); ok && imports[x.Name] != "" {
+                    refs[imports[x.Name]+"."+t.Sel.Name] = true
+                    return false
+                }
+            case *ast.Ident:
+                refs[own+"."+t.Name] = true
+            }
+            return true
+        })
+        return nil
+    })
+    return sortedKeys(refs)
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+    "encoding/json"
+    "path/filepath"
+    "testing"
+)
+
+// TestFeatureGatesGolden extracts the fixture feature gates and their component links.
+func TestFeatureGatesGolden(t *testing.T) {
+    root, components := fixtureComponents(t)
+    got, err := json.MarshalIndent(extractFeatureGates(components, root), "", "  ")
+    if err != nil { t.Fatal(err) }
+    checkGolden(t, filepath.Join("testdata", "golden", "feature_gates.json"), append(got, '\n'))
+}
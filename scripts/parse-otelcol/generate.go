@@ -0,0 +1,393 @@
+package main
+
+import (
+    "bytes"
+    "flag"
+    "fmt"
+    "os"
+    "regexp"
+    "sort"
+    "strings"
+
+    "gopkg.in/yaml.v3"
+)
+
+// Placeholder values for string settings with a recognized Format and no default.
+var formatPlaceholders = map[string]string{
+    "hostport": "localhost:4317",
+    "url":      "http://localhost:4318",
+    "duration": "10s",
+}
+
+// runGenerate implements:
+//   generate --schema=configs.json [--full] --component=<type>/<name>
+//   generate --schema=configs.json [--full] --receiver=otlp [--processors=batch,...] --exporter=debug [--signal=traces]
+func runGenerate(args []string) int {
+    fs := flag.NewFlagSet("generate", flag.ExitOnError)
+    schemaPath := fs.String("schema", "", "Extracted configs JSON to generate from")
+    component := fs.String("component", "", "Emit a snippet for this component (e.g. receiver/otlp)")
+    full := fs.Bool("full", false, "Emit every setting instead of only the required ones")
+    receivers := fs.String("receiver", "", "Comma-separated receivers for a whole-collector config")
+    processors := fs.String("processors", "", "Comma-separated processors, in pipeline order")
+    exporters := fs.String("exporter", "", "Comma-separated exporters for a whole-collector config")
+    signal := fs.String("signal", "", "Pipeline signal (default: the first signal every component supports)")
+    outPath := fs.String("output", "-", "Output file (- for stdout)")
+    fs.Usage = func() {
+        fmt.Fprintln(os.Stderr, "Usage: go run . generate --schema=configs.json [--full] --component=<type>/<name>")
+        fmt.Fprintln(os.Stderr, "       go run . generate --schema=configs.json [--full] --receiver=otlp [--processors=batch] --exporter=debug [--signal=traces]")
+        fs.PrintDefaults()
+    }
+    _ = fs.Parse(args)
+    chain := *receivers != "" || *processors != "" || *exporters != ""
+    if *schemaPath == "" || (*component != "") == chain || (chain && (*receivers == "" || *exporters == "")) {
+        fs.Usage()
+        return 2
+    }
+    data, err := loadExtractedData(*schemaPath)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "generate: %v\n", err)
+        return 1
+    }
+    g := &configGenerator{idx: newSchemaIndex(data), full: *full}
+    var doc *yaml.Node
+    if chain {
+        doc, err = g.collectorConfig(splitList(*receivers), splitList(*processors), splitList(*exporters), *signal)
+    } else {
+        doc, err = g.snippet(*component)
+    }
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "generate: %v\n", err)
+        return 1
+    }
+    var buf bytes.Buffer
+    enc := yaml.NewEncoder(&buf)
+    enc.SetIndent(2)
+    if err := enc.Encode(doc); err != nil {
+        fmt.Fprintf(os.Stderr, "generate: %v\n", err)
+        return 1
+    }
+    _ = enc.Close()
+    if *outPath == "-" {
+        fmt.Print(buf.String())
+        return 0
+    }
+    if err := os.WriteFile(*outPath, buf.Bytes(), 0644); err != nil {
+        fmt.Fprintf(os.Stderr, "generate: %v\n", err)
+        return 1
+    }
+    return 0
+}
+
+func splitList(s string) []string {
+    var out []string
+    for _, p := range strings.Split(s, ",") {
+        if p = strings.TrimSpace(p); p != "" { out = append(out, p) }
+    }
+    return out
+}
+
+// configGenerator builds starter YAML from extracted schemas: defaults first, then the
+// first enum value, then a placeholder for the field's Format or type.
+type configGenerator struct {
+    idx  *schemaIndex
+    full bool // every non-deprecated setting; otherwise only required ones
+    // Per component: rank orders a node's children by the first field below them (struct
+    // order); force and skip are the keys the component's constraints demand or exclude.
+    comp  *Component
+    rank  map[*schemaNode]int
+    force map[string]bool
+    skip  map[string]bool
+}
+
+// snippet emits `<section>: {<name>: ...}` for one component.
+func (g *configGenerator) snippet(key string) (*yaml.Node, error) {
+    c := g.idx.components[key]
+    if c == nil { return nil, fmt.Errorf("component %s not found", key) }
+    root := mappingNode()
+    if c.Type == "service" {
+        addEntry(root, "service", c.Description, g.componentNode(c))
+        return root, nil
+    }
+    addEntry(root, sectionForKind(c.Type), "", g.section([]*Component{c}))
+    return root, nil
+}
+
+// collectorConfig assembles receivers, processors and exporters plus a service pipeline using them.
+func (g *configGenerator) collectorConfig(receivers, processors, exporters []string, signal string) (*yaml.Node, error) {
+    var all []*Component
+    lookup := func(kind string, ids []string) ([]*Component, error) {
+        var out []*Component
+        for _, id := range ids {
+            c := g.idx.component(kind, id)
+            if c == nil { return nil, fmt.Errorf("unknown %s type %q", kind, componentTypeFromID(id)) }
+            out = append(out, c)
+        }
+        all = append(all, out...)
+        return out, nil
+    }
+    rs, err := lookup("receiver", receivers)
+    if err != nil { return nil, err }
+    ps, err := lookup("processor", processors)
+    if err != nil { return nil, err }
+    es, err := lookup("exporter", exporters)
+    if err != nil { return nil, err }
+    signal, err = pipelineSignal(g.idx.data.Document.Signals, all, signal)
+    if err != nil { return nil, err }
+
+    root := mappingNode()
+    for _, sec := range []struct {
+        name  string
+        ids   []string
+        comps []*Component
+    }{{"receivers", receivers, rs}, {"processors", processors, ps}, {"exporters", exporters, es}} {
+        if len(sec.comps) == 0 { continue }
+        section := mappingNode()
+        for i, c := range sec.comps {
+            addEntry(section, sec.ids[i], c.Description, g.componentNode(c))
+        }
+        addEntry(root, sec.name, "", section)
+    }
+    pipeline := mappingNode()
+    addEntry(pipeline, "receivers", "", flowSequence(receivers))
+    if len(processors) > 0 { addEntry(pipeline, "processors", "", flowSequence(processors)) }
+    addEntry(pipeline, "exporters", "", flowSequence(exporters))
+    pipelines := mappingNode()
+    addEntry(pipelines, signal, "", pipeline)
+    service := mappingNode()
+    addEntry(service, "pipelines", "", pipelines)
+    addEntry(root, "service", "", service)
+    return root, nil
+}
+
+// pipelineSignal checks the requested signal against every component's declared signals,
+// or picks the first one they all support.
+func pipelineSignal(signals []string, comps []*Component, want string) (string, error) {
+    if len(signals) == 0 { signals = []string{"traces", "metrics", "logs"} }
+    supports := func(c *Component, s string) bool {
+        return len(c.Signals) == 0 || containsToken(c.Signals, s)
+    }
+    if want != "" {
+        for _, c := range comps {
+            if !supports(c, want) { return "", fmt.Errorf("%s does not support %s", componentKey(c), want) }
+        }
+        return want, nil
+    }
+    for _, s := range signals {
+        ok := true
+        for _, c := range comps { ok = ok && supports(c, s) }
+        if ok { return s, nil }
+    }
+    return "", fmt.Errorf("no signal is supported by every component")
+}
+
+// section emits the section mapping holding the given components under their type names.
+func (g *configGenerator) section(comps []*Component) *yaml.Node {
+    m := mappingNode()
+    for _, c := range comps { addEntry(m, c.Name, c.Description, g.componentNode(c)) }
+    return m
+}
+
+// componentNode is the configuration of one component; null when nothing is emitted.
+func (g *configGenerator) componentNode(c *Component) *yaml.Node {
+    tree := g.idx.tree(c)
+    g.comp, g.rank = c, fieldRanks(tree, c.Config.Fields)
+    g.force, g.skip = constraintKeys(c.Constraints, g.full)
+    m := g.mapping(tree, nil)
+    if len(m.Content) == 0 { return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null"} }
+    return m
+}
+
+// mapping emits the children of a nested schema node that the current mode wants.
+func (g *configGenerator) mapping(sn *schemaNode, path []string) *yaml.Node {
+    m := mappingNode()
+    keys := make([]string, 0, len(sn.children))
+    for k := range sn.children {
+        if k != "[]" && k != "{key}" { keys = append(keys, k) }
+    }
+    sort.SliceStable(keys, func(i, j int) bool {
+        ri, rj := g.rank[sn.children[keys[i]]], g.rank[sn.children[keys[j]]]
+        if ri != rj { return ri < rj }
+        return keys[i] < keys[j]
+    })
+    for _, k := range keys {
+        child, childPath := sn.children[k], append(append([]string{}, path...), k)
+        if !g.wanted(child, childPath) { continue }
+        if f := child.field; f != nil {
+            addEntry(m, k, fieldComment(f), g.value(child, childPath))
+            continue
+        }
+        addEntry(m, k, "", g.nested(child, childPath))
+    }
+    // Maps of objects without a field of their own (e.g. map[string]Struct squashed in)
+    if values := sn.children["{key}"]; values != nil && g.full && len(keys) == 0 {
+        addEntry(m, g.exampleKey(path), "", g.mapping(values, append(append([]string{}, path...), "{key}")))
+    }
+    return m
+}
+
+// exampleKey names the example entry of a map: a signal for service pipelines, whose
+// keys are pipeline IDs, "example" otherwise.
+func (g *configGenerator) exampleKey(path []string) string {
+    signals := g.idx.data.Document.Signals
+    if g.comp.Type == "service" && len(path) == 1 && path[0] == "pipelines" && len(signals) > 0 { return signals[0] }
+    return "example"
+}
+
+// nested emits a mapping node, a one-entry list for "[]" items or a one-entry map for "{key}" values.
+func (g *configGenerator) nested(sn *schemaNode, path []string) *yaml.Node {
+    if items := sn.children["[]"]; items != nil && len(sn.children) == 1 {
+        seq := &yaml.Node{Kind: yaml.SequenceNode}
+        if len(items.children) > 0 { seq.Content = append(seq.Content, g.mapping(items, append(path, "[]"))) }
+        return seq
+    }
+    return g.mapping(sn, path)
+}
+
+// wanted reports whether a node is emitted: every non-deprecated setting in full mode,
+// required settings (and the mappings leading to them) otherwise. Constraint keys override both.
+func (g *configGenerator) wanted(sn *schemaNode, path []string) bool {
+    key := strings.Join(path, ".")
+    if g.skip[key] { return false }
+    if g.force[key] { return true }
+    if f := sn.field; f != nil {
+        if f.Deprecated || f.Type == "custom" { return false }
+        return g.full || f.Required
+    }
+    for k, c := range sn.children {
+        if k == "{key}" && !g.full { continue }
+        if g.wanted(c, append(append([]string{}, path...), k)) { return true }
+    }
+    return false
+}
+
+// constraintKeys returns the keys a generated config must set (the first alternative of
+// anyOf/oneOf, every key of allOf) and, in full mode, the alternatives it must leave out
+// (the rest of oneOf/atMostOne).
+func constraintKeys(constraints []Constraint, full bool) (force, skip map[string]bool) {
+    force, skip = map[string]bool{}, map[string]bool{}
+    for _, cs := range constraints {
+        if len(cs.KeyTokens) == 0 { continue }
+        switch cs.Kind {
+        case "anyOf", "oneOf":
+            force[strings.Join(cs.KeyTokens[0], ".")] = true
+        case "allOf":
+            for _, k := range cs.KeyTokens { force[strings.Join(k, ".")] = true }
+        }
+        if full && (cs.Kind == "oneOf" || cs.Kind == "atMostOne") {
+            for _, k := range cs.KeyTokens[1:] { skip[strings.Join(k, ".")] = true }
+        }
+    }
+    for k := range force { delete(skip, k) }
+    return force, skip
+}
+
+// value picks the generated value for a field node.
+func (g *configGenerator) value(sn *schemaNode, path []string) *yaml.Node {
+    f := sn.field
+    if f.Default != nil && !isEmptyCollection(f.Default) {
+        n := &yaml.Node{}
+        if err := n.Encode(f.Default); err == nil { return n }
+    }
+    // Collections of objects get one example entry built from the element schema
+    if items := sn.children["[]"]; items != nil && len(items.children) > 0 {
+        return &yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{g.mapping(items, append(path, "[]"))}}
+    }
+    if values := sn.children["{key}"]; values != nil && len(values.children) > 0 {
+        m := mappingNode()
+        addEntry(m, g.exampleKey(path), "", g.mapping(values, append(path, "{key}")))
+        return m
+    }
+    if len(f.EnumValues) > 0 { return scalarNode(f.EnumValues[0]) }
+    if f.Format == "pem" {
+        tokens := makePathTokens(fieldKey(f))
+        return scalarNode("/path/to/" + strings.TrimSuffix(tokens[len(tokens)-1], "_file") + ".pem")
+    }
+    if p, ok := formatPlaceholders[f.Format]; ok { return scalarNode(p) }
+    if f.Sensitive && f.Type == "string" { return scalarNode("${env:" + envVarName(g.comp.Name + "_" + fieldKey(f)) + "}") }
+    switch f.Type {
+    case "bool":
+        return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "false"}
+    case "int":
+        return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: "0"}
+    case "double":
+        return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: "0.0"}
+    case "duration":
+        return scalarNode(formatPlaceholders["duration"])
+    case "array", "stringArray":
+        return &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+    case "map", "stringMap":
+        return &yaml.Node{Kind: yaml.MappingNode, Style: yaml.FlowStyle}
+    }
+    return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "", Style: yaml.DoubleQuotedStyle}
+}
+
+func isEmptyCollection(v any) bool {
+    switch t := v.(type) {
+    case []any:
+        return len(t) == 0
+    case map[string]any:
+        return len(t) == 0
+    }
+    return false
+}
+
+var nonEnvChars = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// envVarName derives an environment variable name for a secret, e.g. "auth.token" -> "AUTH_TOKEN".
+func envVarName(key string) string {
+    return strings.Trim(strings.ToUpper(nonEnvChars.ReplaceAllString(key, "_")), "_")
+}
+
+// fieldComment is the YAML comment placed above a generated setting.
+func fieldComment(f *ConfigField) string {
+    desc := strings.TrimSpace(f.Description)
+    if f.Required && f.Default == nil {
+        if desc != "" { desc += "\n" }
+        desc += "Required."
+    }
+    return desc
+}
+
+// fieldRanks maps each schema node to the index of the first field below it.
+func fieldRanks(tree *schemaNode, fields []ConfigField) map[*schemaNode]int {
+    index := map[*ConfigField]int{}
+    for i := range fields { index[&fields[i]] = i }
+    ranks := map[*schemaNode]int{}
+    var visit func(n *schemaNode) int
+    visit = func(n *schemaNode) int {
+        r := len(fields)
+        if n.field != nil { r = index[n.field] }
+        for _, c := range n.children {
+            if cr := visit(c); cr < r { r = cr }
+        }
+        ranks[n] = r
+        return r
+    }
+    visit(tree)
+    return ranks
+}
+
+// sectionForKind maps a component type to its config section ("receiver" -> "receivers").
+func sectionForKind(kind string) string {
+    for _, s := range componentSections {
+        if s.kind == kind { return s.section }
+    }
+    return kind + "s"
+}
+
+func mappingNode() *yaml.Node { return &yaml.Node{Kind: yaml.MappingNode} }
+
+func scalarNode(v string) *yaml.Node { return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v} }
+
+func flowSequence(items []string) *yaml.Node {
+    seq := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+    for _, it := range items { seq.Content = append(seq.Content, scalarNode(it)) }
+    return seq
+}
+
+// addEntry appends key: value to a mapping node, with comment as the key's head comment.
+func addEntry(m *yaml.Node, key, comment string, value *yaml.Node) {
+    k := scalarNode(key)
+    k.HeadComment = comment
+    m.Content = append(m.Content, k, value)
+}
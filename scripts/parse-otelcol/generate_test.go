@@ -0,0 +1,50 @@
+package main
+
+import (
+    "bytes"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "gopkg.in/yaml.v3"
+)
+
+// TestGenerateGolden generates starter configs from the fixture schema, compared with
+// testdata/golden/generate/<case>.yaml: required-only and --full snippets, and a
+// receiver -> processor -> exporter chain that must then pass validate (constraint keys
+// included) so the generated config runs as is.
+func TestGenerateGolden(t *testing.T) {
+    idx := fixtureIndex(t)
+    tests := []struct {
+        name string
+        full bool
+        gen  func(g *configGenerator) (*yaml.Node, error)
+    }{
+        {"receiver_otlp", false, func(g *configGenerator) (*yaml.Node, error) { return g.snippet("receiver/otlp") }},
+        {"exporter_otlp", false, func(g *configGenerator) (*yaml.Node, error) { return g.snippet("exporter/otlp") }},
+        {"exporter_otlp_full", true, func(g *configGenerator) (*yaml.Node, error) { return g.snippet("exporter/otlp") }},
+        {"processor_routing_full", true, func(g *configGenerator) (*yaml.Node, error) { return g.snippet("processor/routing") }},
+        {"chain", false, func(g *configGenerator) (*yaml.Node, error) {
+            return g.collectorConfig([]string{"otlp"}, []string{"batch"}, []string{"otlp", "otlp/backup"}, "")
+        }},
+        {"chain_full", true, func(g *configGenerator) (*yaml.Node, error) {
+            return g.collectorConfig([]string{"otlp"}, []string{"batch"}, []string{"otlp", "otlp/backup"}, "")
+        }},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            doc, err := tt.gen(&configGenerator{idx: idx, full: tt.full})
+            if err != nil { t.Fatal(err) }
+            var buf bytes.Buffer
+            enc := yaml.NewEncoder(&buf)
+            enc.SetIndent(2)
+            if err := enc.Encode(doc); err != nil { t.Fatal(err) }
+            _ = enc.Close()
+            checkGolden(t, filepath.Join("testdata", "golden", "generate", tt.name+".yaml"), buf.Bytes())
+            if !strings.HasPrefix(tt.name, "chain") { return }
+            issues, err := validateConfig(idx, tt.name+".yaml", buf.Bytes(), validateOptions{})
+            if err != nil { t.Fatal(err) }
+            checkIssues(t, issues)
+        })
+    }
+}
@@ -4,6 +4,7 @@ go 1.25
 
 require (
 	golang.org/x/tools v0.37.0
+	gopkg.in/yaml.v3 v3.0.1
 	modernc.org/sqlite v1.30.1
 )
 
@@ -0,0 +1,92 @@
+package main
+
+import (
+    "flag"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite testdata/golden from the current output")
+
+// schemaFixture is a hand-written extracted schema for tests of the commands reading one.
+const schemaFixture = "testdata/schema/configs.json"
+
+// fixtureRoot is a miniature collector checkout with one component per extractor feature.
+const fixtureRoot = "testdata/collector"
+
+// fixturePackages loads the fixture tree into a fresh package cache, dropped again when
+// the test ends, and returns its absolute path.
+func fixturePackages(t *testing.T) string {
+    t.Helper()
+    resetPackageCache()
+    t.Cleanup(resetPackageCache)
+    root, err := filepath.Abs(fixtureRoot)
+    if err != nil { t.Fatal(err) }
+    prewarmPackageCache(root)
+    return root
+}
+
+// fixtureComponents extracts every fixture component (in untyped mode).
+func fixtureComponents(t *testing.T) (string, []Component) {
+    t.Helper()
+    root := fixturePackages(t)
+    return root, extractTasks(componentTasks(root, false), nil, root)
+}
+
+// fixtureIndex loads schemaFixture into a schema index.
+func fixtureIndex(t *testing.T) *schemaIndex {
+    t.Helper()
+    data, err := loadExtractedData(schemaFixture)
+    if err != nil { t.Fatal(err) }
+    return newSchemaIndex(data)
+}
+
+// checkIssues compares validation findings, formatted as on the command line, with want.
+func checkIssues(t *testing.T, issues []ValidationIssue, want ...string) {
+    t.Helper()
+    var got []string
+    for _, is := range issues { got = append(got, is.String()) }
+    if strings.Join(got, "\n") != strings.Join(want, "\n") { t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n")) }
+}
+
+// checkGolden compares got with the golden file at path, or rewrites it with -update.
+func checkGolden(t *testing.T, path string, got []byte) {
+    t.Helper()
+    if *updateGolden {
+        if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil { t.Fatal(err) }
+        if err := os.WriteFile(path, got, 0644); err != nil { t.Fatal(err) }
+        return
+    }
+    want, err := os.ReadFile(path)
+    if err != nil { t.Fatalf("%v (run with -update to create it)", err) }
+    if string(want) == string(got) { return }
+    wl, gl := strings.Split(string(want), "\n"), strings.Split(string(got), "\n")
+    for i := 0; i < len(wl) || i < len(gl); i++ {
+        var w, g string
+        if i < len(wl) { w = wl[i] }
+        if i < len(gl) { g = gl[i] }
+        if w != g {
+            t.Fatalf("%s differs at line %d:\n  want: %s\n   got: %s\n(run with -update to accept)", path, i+1, w, g)
+        }
+    }
+}
+
+// writeTree writes files, keyed by slash-separated path relative to dir.
+func writeTree(t *testing.T, dir string, files map[string]string) {
+    t.Helper()
+    for name, content := range files {
+        path := filepath.Join(dir, filepath.FromSlash(name))
+        if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil { t.Fatal(err) }
+        if err := os.WriteFile(path, []byte(content), 0644); err != nil { t.Fatal(err) }
+    }
+}
+
+// resetPackageCache drops packages loaded by earlier tests, whose sources may have changed.
+func resetPackageCache() {
+    globalPkgCache.mu.Lock()
+    globalPkgCache.byImport = map[string]*packageContext{}
+    globalPkgCache.byDir = map[string]*packageContext{}
+    globalPkgCache.mu.Unlock()
+}
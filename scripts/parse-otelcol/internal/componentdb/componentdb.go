@@ -0,0 +1,1176 @@
+// Package componentdb stores extractor JSON documents in the SQLite database shipped with
+// the app and reads them back (--export, --search). build_database.go is its command line;
+// callers open the database with a driver registered as "sqlite".
+package componentdb
+
+import (
+    "database/sql"
+    "encoding/json"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "regexp"
+    "sort"
+    "strconv"
+    "strings"
+    "text/tabwriter"
+    "unicode"
+)
+
+type DocumentSchema struct {
+    Sections               []string `json:"sections"`
+    Signals                []string `json:"signals"`
+    ComponentIDPattern     string   `json:"component_id_pattern"`
+    SupportsInstanceSuffix bool     `json:"supports_instance_suffix"`
+    PipelineShape          struct {
+        Receivers  bool `json:"receivers"`
+        Processors bool `json:"processors"`
+        Exporters  bool `json:"exporters"`
+        Connectors bool `json:"connectors"`
+    } `json:"pipeline_shape"`
+    PipelineRefs map[string][]string `json:"pipeline_refs"`
+    Telemetry struct {
+        MetricsLevels []string `json:"metrics_levels"`
+        DefaultLevel  string   `json:"default_level"`
+    } `json:"telemetry"`
+}
+
+type Extracted struct {
+    Version    string         `json:"version"`
+    Components []Component    `json:"components"`
+    Document   DocumentSchema `json:"document"`
+    // Service is the service section's schema, stored as a components row of type "service"
+    Service    *Component     `json:"service,omitempty"`
+    Releases   []string       `json:"releases,omitempty"`
+    FeatureGates []FeatureGate `json:"feature_gates,omitempty"`
+    // Shared config structs referenced by "$ref" fields, stored once per version
+    Definitions map[string]Definition `json:"definitions,omitempty"`
+}
+
+type Definition struct {
+    Package string  `json:"package"`
+    Type    string  `json:"type"`
+    Fields  []Field `json:"fields"`
+}
+
+type Component struct {
+    Name        string       `json:"name"`
+    Type        string       `json:"type"`
+    Module      string       `json:"module,omitempty"`
+    Description string       `json:"description"`
+    Config      ConfigSchema `json:"config"`
+    Constraints []Constraint `json:"constraints"`
+    Signals     []string     `json:"signals,omitempty"`
+    SignalPairs []SignalPair `json:"signal_pairs,omitempty"`
+    Stability   map[string]string `json:"stability,omitempty"`
+    Distributions      []string            `json:"distributions,omitempty"`
+    Releases           []string            `json:"releases,omitempty"`
+    Codeowners         []string            `json:"codeowners,omitempty"`
+    Metrics            []EmittedMetric     `json:"metrics,omitempty"`
+    ResourceAttributes []ResourceAttribute `json:"resource_attributes,omitempty"`
+    CustomUnmarshal    bool                `json:"custom_unmarshal,omitempty"`
+}
+
+type EmittedMetric struct {
+    Name        string            `json:"name"`
+    Description string            `json:"description,omitempty"`
+    Unit        string            `json:"unit,omitempty"`
+    Type        string            `json:"type,omitempty"`
+    Enabled     bool              `json:"enabled"`
+    Attributes  []MetricAttribute `json:"attributes,omitempty"`
+}
+
+type MetricAttribute struct {
+    Name        string   `json:"name"`
+    Description string   `json:"description,omitempty"`
+    Type        string   `json:"type,omitempty"`
+    Enum        []string `json:"enum,omitempty"`
+}
+
+type ResourceAttribute struct {
+    Name        string   `json:"name"`
+    Description string   `json:"description,omitempty"`
+    Type        string   `json:"type,omitempty"`
+    Enum        []string `json:"enum,omitempty"`
+    Enabled     bool     `json:"enabled"`
+}
+
+type FeatureGate struct {
+    ID           string   `json:"id"`
+    Stage        string   `json:"stage"`
+    Description  string   `json:"description,omitempty"`
+    ReferenceURL string   `json:"reference_url,omitempty"`
+    FromVersion  string   `json:"from_version,omitempty"`
+    ToVersion    string   `json:"to_version,omitempty"`
+    Package      string   `json:"package"`
+    Components   []string `json:"components,omitempty"`
+}
+
+type SignalPair struct {
+    From string `json:"from"`
+    To   string `json:"to"`
+}
+
+type ConfigSchema struct {
+    Fields   []Field  `json:"fields"`
+    Examples []string `json:"examples"`
+}
+
+type Field struct {
+    Name        string            `json:"name"`
+    Type        string            `json:"type"`
+    Required    bool              `json:"required"`
+    Default     any               `json:"default"`
+    Description string            `json:"description"`
+    PathTokens  []string          `json:"path_tokens"`
+    EnumValues  []string          `json:"enum_values"`
+    Format      string            `json:"format"`
+    Unit        string            `json:"unit"`
+    Sensitive   bool              `json:"sensitive"`
+    ItemType    string            `json:"item_type"`
+    RefKind     string            `json:"ref_kind"`
+    RefScope    string            `json:"ref_scope"`
+    Validation  map[string]string `json:"validation"`
+    CustomUnmarshal bool          `json:"custom_unmarshal,omitempty"`
+    Deprecated  bool              `json:"deprecated,omitempty"`
+    Replacement string            `json:"replacement,omitempty"`
+    DeclaredIn  string            `json:"declared_in,omitempty"`
+    DeclaredPath []string         `json:"declared_path,omitempty"`
+    Ref         string            `json:"$ref,omitempty"`
+    Overrides   []Field           `json:"overrides,omitempty"`
+    Omit        []string          `json:"omit,omitempty"`
+}
+
+type Constraint struct {
+    Kind      string     `json:"kind"`
+    KeyTokens [][]string `json:"keys"`
+    Message   string     `json:"message"`
+}
+
+// ReadDocuments loads the extractor JSON files matching pattern (a glob or a single
+// path), oldest collector version first.
+func ReadDocuments(pattern string) ([]*Extracted, error) {
+    files, err := expandGlob(pattern)
+    if err != nil || len(files) == 0 { return nil, fmt.Errorf("no input JSON files match %q", pattern) }
+    docs := make([]*Extracted, 0, len(files))
+    for _, p := range files {
+        data, err := os.ReadFile(p)
+        if err != nil { return nil, fmt.Errorf("read %s: %v", p, err) }
+        var doc Extracted
+        if err := json.Unmarshal(data, &doc); err != nil { return nil, fmt.Errorf("parse %s: %v", p, err) }
+        if strings.TrimSpace(doc.Version) == "" { return nil, fmt.Errorf("parse %s: missing version", p) }
+        docs = append(docs, &doc)
+    }
+    sortByVersion(docs)
+    return docs, nil
+}
+
+// Build loads docs into db, each collector version side by side with the ones already
+// stored; a version loaded again replaces its previous rows. With appendTo the database
+// must have been written by this schema version (or be empty).
+func Build(db *sql.DB, docs []*Extracted, appendTo bool) error {
+    if _, err := db.Exec(`PRAGMA journal_mode=WAL; PRAGMA synchronous=NORMAL; PRAGMA foreign_keys=ON;`); err != nil {
+        return fmt.Errorf("pragma: %v", err)
+    }
+    if appendTo {
+        if err := checkSchemaVersion(db); err != nil { return fmt.Errorf("append: %v", err) }
+    }
+    if err := createSchema(db); err != nil { return fmt.Errorf("schema: %v", err) }
+    for _, doc := range docs {
+        if err := loadDocument(db, doc); err != nil { return fmt.Errorf("load %s: %v", doc.Version, err) }
+    }
+    if err := updateMeta(db); err != nil { return fmt.Errorf("meta: %v", err) }
+    return nil
+}
+
+func expandGlob(pattern string) ([]string, error) {
+    // Support simple globbing and literal files
+    if strings.ContainsAny(pattern, "*?[]") {
+        return filepath.Glob(pattern)
+    }
+    // Non-glob; check existence
+    if _, err := os.Stat(pattern); err != nil { return nil, err }
+    return []string{pattern}, nil
+}
+
+// sortByVersion orders documents oldest to newest so the last one loaded is the latest release.
+func sortByVersion(docs []*Extracted) {
+    sort.SliceStable(docs, func(i, j int) bool { return compareVersions(docs[i].Version, docs[j].Version) < 0 })
+}
+
+// compareVersions compares collector tags like "v0.135.0" numerically; non-numeric parts compare as strings.
+func compareVersions(a, b string) int {
+    pa := strings.Split(strings.TrimPrefix(a, "v"), ".")
+    pb := strings.Split(strings.TrimPrefix(b, "v"), ".")
+    for i := 0; i < len(pa) || i < len(pb); i++ {
+        var x, y string
+        if i < len(pa) { x = pa[i] }
+        if i < len(pb) { y = pb[i] }
+        nx, errx := strconv.Atoi(x)
+        ny, erry := strconv.Atoi(y)
+        switch {
+        case errx == nil && erry == nil:
+            if nx != ny { if nx < ny { return -1 }; return 1 }
+        case x != y:
+            if x < y { return -1 }
+            return 1
+        }
+    }
+    return 0
+}
+
+// schemaVersion is meta.schema_version of the databases written here: 1 held a single
+// collector version, 2 stores versions side by side.
+const schemaVersion = "2"
+
+// checkSchemaVersion refuses to --append to a database laid out by another builder
+// version; CREATE TABLE IF NOT EXISTS would leave its old tables in place.
+func checkSchemaVersion(db *sql.DB) error {
+    var tables int
+    if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table'`).Scan(&tables); err != nil { return err }
+    if tables == 0 { return nil }
+    var v string
+    if err := db.QueryRow(`SELECT value FROM meta WHERE key = 'schema_version'`).Scan(&v); err != nil {
+        return fmt.Errorf("no meta.schema_version (%v); rebuild it from all versions without --append", err)
+    }
+    if v != schemaVersion {
+        return fmt.Errorf("schema version %s, this builder writes version %s; rebuild it from all versions without --append", v, schemaVersion)
+    }
+    return nil
+}
+
+func createSchema(db *sql.DB) error {
+    stmts := []string{
+        `CREATE TABLE IF NOT EXISTS meta (key TEXT PRIMARY KEY, value TEXT);`,
+        `CREATE TABLE IF NOT EXISTS versions (
+            version TEXT PRIMARY KEY,
+            ordinal INTEGER NOT NULL
+        );`,
+        `CREATE TABLE IF NOT EXISTS document (
+            id INTEGER PRIMARY KEY,
+            version TEXT NOT NULL UNIQUE,
+            sections_json TEXT NOT NULL,
+            signals_json TEXT NOT NULL,
+            pipeline_shape_json TEXT NOT NULL,
+            telemetry_levels_json TEXT NOT NULL,
+            default_level TEXT NOT NULL,
+            pipeline_refs_json TEXT
+        );`,
+        `CREATE TABLE IF NOT EXISTS components (
+            id INTEGER PRIMARY KEY,
+            name TEXT NOT NULL,
+            type TEXT NOT NULL,
+            description TEXT,
+            version TEXT NOT NULL,
+            custom_unmarshal INTEGER NOT NULL DEFAULT 0,
+            module TEXT
+        );`,
+        `CREATE INDEX IF NOT EXISTS idx_components_type_name ON components(type,name);`,
+        `CREATE INDEX IF NOT EXISTS idx_components_version ON components(version,type,name);`,
+        `CREATE TABLE IF NOT EXISTS definitions (
+            id INTEGER PRIMARY KEY,
+            version TEXT NOT NULL,
+            def_key TEXT NOT NULL,
+            package TEXT NOT NULL,
+            type TEXT NOT NULL,
+            UNIQUE(version, def_key)
+        );`,
+        `CREATE INDEX IF NOT EXISTS idx_definitions_type ON definitions(type, version);`,
+        // A component embedding a definition at path_json; its differing fields are rows with
+        // ref_id set, omit_json lists the definition keys it lacks
+        `CREATE TABLE IF NOT EXISTS component_definitions (
+            id INTEGER PRIMARY KEY,
+            component_id INTEGER NOT NULL REFERENCES components(id) ON DELETE CASCADE,
+            definition_id INTEGER NOT NULL REFERENCES definitions(id) ON DELETE CASCADE,
+            position INTEGER NOT NULL,
+            path_json TEXT NOT NULL,
+            omit_json TEXT
+        );`,
+        `CREATE INDEX IF NOT EXISTS idx_component_definitions_component ON component_definitions(component_id);`,
+        `CREATE INDEX IF NOT EXISTS idx_component_definitions_definition ON component_definitions(definition_id);`,
+        // Fields belong to a component (component_id) or a definition (definition_id, paths
+        // relative to where it is embedded); ref_path is the key relative to the definition
+        `CREATE TABLE IF NOT EXISTS fields (
+            id INTEGER PRIMARY KEY,
+            component_id INTEGER REFERENCES components(id) ON DELETE CASCADE,
+            definition_id INTEGER REFERENCES definitions(id) ON DELETE CASCADE,
+            ref_id INTEGER REFERENCES component_definitions(id) ON DELETE CASCADE,
+            ref_path TEXT,
+            name TEXT NOT NULL,
+            kind TEXT NOT NULL,
+            required INTEGER NOT NULL,
+            default_json TEXT,
+            description TEXT,
+            format TEXT,
+            unit TEXT,
+            sensitive INTEGER NOT NULL,
+            item_type TEXT,
+            ref_kind TEXT,
+            ref_scope TEXT,
+            validation_json TEXT,
+            custom_unmarshal INTEGER NOT NULL DEFAULT 0,
+            deprecated INTEGER NOT NULL DEFAULT 0,
+            replacement TEXT,
+            declared_in TEXT,
+            declared_path_json TEXT
+        );`,
+        `CREATE INDEX IF NOT EXISTS idx_fields_component ON fields(component_id);`,
+        `CREATE INDEX IF NOT EXISTS idx_fields_declared_in ON fields(declared_in);`,
+        `CREATE INDEX IF NOT EXISTS idx_fields_definition ON fields(definition_id);`,
+        `CREATE INDEX IF NOT EXISTS idx_fields_ref ON fields(ref_id, ref_path);`,
+        `CREATE TABLE IF NOT EXISTS field_paths (
+            field_id INTEGER NOT NULL REFERENCES fields(id) ON DELETE CASCADE,
+            idx INTEGER NOT NULL,
+            token TEXT NOT NULL
+        );`,
+        `CREATE INDEX IF NOT EXISTS idx_field_paths_field ON field_paths(field_id, idx);`,
+        // Every component's full field list, definitions expanded under their embedding path
+        // (ids of expanded rows are negative); the app reads these instead of fields/field_paths
+        `CREATE VIEW IF NOT EXISTS component_fields AS
+            SELECT id, component_id, name, kind, required, default_json, description, format, unit, sensitive,
+                item_type, ref_kind, ref_scope, validation_json, custom_unmarshal, deprecated, replacement
+            FROM fields WHERE component_id IS NOT NULL
+            UNION ALL
+            SELECT -(r.id * 4294967296 + f.id), r.component_id, f.name, f.kind, f.required, f.default_json, f.description, f.format, f.unit, f.sensitive,
+                f.item_type, f.ref_kind, f.ref_scope, f.validation_json, f.custom_unmarshal, f.deprecated, f.replacement
+            FROM component_definitions r JOIN fields f ON f.definition_id = r.definition_id
+            WHERE NOT EXISTS (SELECT 1 FROM fields o WHERE o.ref_id = r.id AND o.ref_path = f.ref_path)
+                AND NOT EXISTS (SELECT 1 FROM json_each(r.omit_json) WHERE value = f.ref_path);`,
+        `CREATE VIEW IF NOT EXISTS component_field_paths AS
+            SELECT field_id, idx, token FROM field_paths
+            UNION ALL
+            SELECT -(r.id * 4294967296 + f.id), p.key, p.value
+            FROM component_definitions r JOIN fields f ON f.definition_id = r.definition_id, json_each(r.path_json) p
+            UNION ALL
+            SELECT -(r.id * 4294967296 + f.id), json_array_length(r.path_json) + fp.idx, fp.token
+            FROM component_definitions r JOIN fields f ON f.definition_id = r.definition_id JOIN field_paths fp ON fp.field_id = f.id;`,
+        `CREATE TABLE IF NOT EXISTS field_enums (
+            field_id INTEGER NOT NULL REFERENCES fields(id) ON DELETE CASCADE,
+            value TEXT NOT NULL
+        );`,
+        `CREATE INDEX IF NOT EXISTS idx_field_enums_field ON field_enums(field_id, value);`,
+        `CREATE TABLE IF NOT EXISTS constraints (
+            id INTEGER PRIMARY KEY,
+            component_id INTEGER NOT NULL REFERENCES components(id) ON DELETE CASCADE,
+            kind TEXT NOT NULL,
+            keys_json TEXT NOT NULL,
+            message TEXT
+        );`,
+        `CREATE INDEX IF NOT EXISTS idx_constraints_component ON constraints(component_id);`,
+        `CREATE TABLE IF NOT EXISTS examples (
+            id INTEGER PRIMARY KEY,
+            component_id INTEGER NOT NULL REFERENCES components(id) ON DELETE CASCADE,
+            yaml TEXT NOT NULL
+        );`,
+        `CREATE INDEX IF NOT EXISTS idx_examples_component ON examples(component_id);`,
+        `CREATE TABLE IF NOT EXISTS component_signals (
+            component_id INTEGER NOT NULL REFERENCES components(id) ON DELETE CASCADE,
+            signal TEXT NOT NULL
+        );`,
+        `CREATE INDEX IF NOT EXISTS idx_component_signals_component ON component_signals(component_id);`,
+        `CREATE TABLE IF NOT EXISTS connector_signal_pairs (
+            component_id INTEGER NOT NULL REFERENCES components(id) ON DELETE CASCADE,
+            from_signal TEXT NOT NULL,
+            to_signal TEXT NOT NULL
+        );`,
+        `CREATE INDEX IF NOT EXISTS idx_connector_signal_pairs_component ON connector_signal_pairs(component_id);`,
+        `CREATE TABLE IF NOT EXISTS component_stability (
+            component_id INTEGER NOT NULL REFERENCES components(id) ON DELETE CASCADE,
+            signal TEXT NOT NULL,
+            level TEXT NOT NULL,
+            PRIMARY KEY (component_id, signal)
+        );`,
+        // metadata.yaml: distributions, code owners, emitted metrics and resource attributes
+        `CREATE TABLE IF NOT EXISTS component_distributions (
+            component_id INTEGER NOT NULL REFERENCES components(id) ON DELETE CASCADE,
+            distribution TEXT NOT NULL
+        );`,
+        `CREATE INDEX IF NOT EXISTS idx_component_distributions ON component_distributions(distribution, component_id);`,
+        // opentelemetry-collector-releases manifests: official distributions shipping the component
+        `CREATE TABLE IF NOT EXISTS component_releases (
+            component_id INTEGER NOT NULL REFERENCES components(id) ON DELETE CASCADE,
+            distribution TEXT NOT NULL
+        );`,
+        `CREATE INDEX IF NOT EXISTS idx_component_releases ON component_releases(distribution, component_id);`,
+        `CREATE TABLE IF NOT EXISTS component_codeowners (
+            component_id INTEGER NOT NULL REFERENCES components(id) ON DELETE CASCADE,
+            owner TEXT NOT NULL
+        );`,
+        `CREATE INDEX IF NOT EXISTS idx_component_codeowners_component ON component_codeowners(component_id);`,
+        `CREATE TABLE IF NOT EXISTS emitted_metrics (
+            id INTEGER PRIMARY KEY,
+            component_id INTEGER NOT NULL REFERENCES components(id) ON DELETE CASCADE,
+            name TEXT NOT NULL,
+            description TEXT,
+            unit TEXT,
+            metric_type TEXT,
+            enabled INTEGER NOT NULL
+        );`,
+        `CREATE INDEX IF NOT EXISTS idx_emitted_metrics_component ON emitted_metrics(component_id);`,
+        `CREATE TABLE IF NOT EXISTS emitted_metric_attributes (
+            metric_id INTEGER NOT NULL REFERENCES emitted_metrics(id) ON DELETE CASCADE,
+            idx INTEGER NOT NULL,
+            name TEXT NOT NULL,
+            description TEXT,
+            type TEXT,
+            enum_json TEXT
+        );`,
+        `CREATE INDEX IF NOT EXISTS idx_emitted_metric_attributes_metric ON emitted_metric_attributes(metric_id, idx);`,
+        `CREATE TABLE IF NOT EXISTS resource_attributes (
+            component_id INTEGER NOT NULL REFERENCES components(id) ON DELETE CASCADE,
+            name TEXT NOT NULL,
+            description TEXT,
+            type TEXT,
+            enum_json TEXT,
+            enabled INTEGER NOT NULL
+        );`,
+        `CREATE INDEX IF NOT EXISTS idx_resource_attributes_component ON resource_attributes(component_id);`,
+        // Feature gates per version, linked to the components declaring or checking them
+        `CREATE TABLE IF NOT EXISTS feature_gates (
+            id INTEGER PRIMARY KEY,
+            version TEXT NOT NULL,
+            gate_id TEXT NOT NULL,
+            stage TEXT NOT NULL,
+            description TEXT,
+            reference_url TEXT,
+            from_version TEXT,
+            to_version TEXT,
+            package TEXT NOT NULL
+        );`,
+        `CREATE INDEX IF NOT EXISTS idx_feature_gates_version ON feature_gates(version, gate_id);`,
+        `CREATE TABLE IF NOT EXISTS component_feature_gates (
+            component_id INTEGER NOT NULL REFERENCES components(id) ON DELETE CASCADE,
+            gate_id INTEGER NOT NULL REFERENCES feature_gates(id) ON DELETE CASCADE,
+            PRIMARY KEY (component_id, gate_id)
+        );`,
+        `CREATE INDEX IF NOT EXISTS idx_component_feature_gates_gate ON component_feature_gates(gate_id);`,
+        // Full-text index over components (field_id NULL) and their fields, including the
+        // definition fields they embed (under the embedding path) for --search;
+        // the default tokenizer splits insecure_skip_verify and tls.insecure into words
+        `CREATE VIRTUAL TABLE IF NOT EXISTS search_index USING fts5(
+            version UNINDEXED,
+            component_id UNINDEXED,
+            field_id UNINDEXED,
+            component,
+            field,
+            path,
+            description
+        );`,
+    }
+    for _, s := range stmts {
+        if _, err := db.Exec(s); err != nil { return err }
+    }
+    return nil
+}
+
+// loadDocument stores one collector version. Rows already present for the same
+// version are replaced so re-ingesting a JSON file is idempotent.
+func loadDocument(db *sql.DB, d *Extracted) error {
+    tx, err := db.Begin()
+    if err != nil { return err }
+    defer func() { _ = tx.Rollback() }()
+
+    // Drop any previous load of this version (fields/paths/enums/constraints/examples cascade)
+    if _, err := tx.Exec(`DELETE FROM components WHERE version = ?`, d.Version); err != nil { return err }
+    if _, err := tx.Exec(`DELETE FROM definitions WHERE version = ?`, d.Version); err != nil { return err }
+    if _, err := tx.Exec(`DELETE FROM document WHERE version = ?`, d.Version); err != nil { return err }
+    if _, err := tx.Exec(`DELETE FROM feature_gates WHERE version = ?`, d.Version); err != nil { return err }
+    if _, err := tx.Exec(`DELETE FROM search_index WHERE version = ?`, d.Version); err != nil { return err }
+    if _, err := tx.Exec(`INSERT OR REPLACE INTO versions(version,ordinal) VALUES(?,0)`, d.Version); err != nil { return err }
+
+    // document
+    sec, _ := json.Marshal(d.Document.Sections)
+    sig, _ := json.Marshal(d.Document.Signals)
+    pipe := map[string]bool{
+        "receivers": d.Document.PipelineShape.Receivers,
+        "processors": d.Document.PipelineShape.Processors,
+        "exporters": d.Document.PipelineShape.Exporters,
+        "connectors": d.Document.PipelineShape.Connectors,
+    }
+    pipeJSON, _ := json.Marshal(pipe)
+    levelsJSON, _ := json.Marshal(d.Document.Telemetry.MetricsLevels)
+    refsJSON := mustJSON(d.Document.PipelineRefs)
+    if _, err := tx.Exec(`INSERT INTO document(version,sections_json,signals_json,pipeline_shape_json,telemetry_levels_json,default_level,pipeline_refs_json)
+        VALUES(?,?,?,?,?,?,?)`, d.Version, string(sec), string(sig), string(pipeJSON), string(levelsJSON), d.Document.Telemetry.DefaultLevel, nullIfEmpty(refsJSON)); err != nil {
+        return err
+    }
+
+    // components and related; ids are assigned by SQLite so multiple versions can share the tables
+    compStmt, err := tx.Prepare(`INSERT INTO components(name,type,description,version,custom_unmarshal,module) VALUES(?,?,?,?,?,?)`)
+    if err != nil { return err }
+    defer compStmt.Close()
+
+    fieldStmt, err := tx.Prepare(`INSERT INTO fields(component_id,definition_id,ref_id,ref_path,name,kind,required,default_json,description,format,unit,sensitive,item_type,ref_kind,ref_scope,validation_json,custom_unmarshal,deprecated,replacement,declared_in,declared_path_json)
+        VALUES(?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`)
+    if err != nil { return err }
+    defer fieldStmt.Close()
+
+    defStmt, err := tx.Prepare(`INSERT INTO definitions(version,def_key,package,type) VALUES(?,?,?,?)`)
+    if err != nil { return err }
+    defer defStmt.Close()
+
+    refStmt, err := tx.Prepare(`INSERT INTO component_definitions(component_id,definition_id,position,path_json,omit_json) VALUES(?,?,?,?,?)`)
+    if err != nil { return err }
+    defer refStmt.Close()
+
+    pathStmt, err := tx.Prepare(`INSERT INTO field_paths(field_id,idx,token) VALUES(?,?,?)`)
+    if err != nil { return err }
+    defer pathStmt.Close()
+
+    enumStmt, err := tx.Prepare(`INSERT INTO field_enums(field_id,value) VALUES(?,?)`)
+    if err != nil { return err }
+    defer enumStmt.Close()
+
+    consStmt, err := tx.Prepare(`INSERT INTO constraints(component_id,kind,keys_json,message) VALUES(?,?,?,?)`)
+    if err != nil { return err }
+    defer consStmt.Close()
+
+    exStmt, err := tx.Prepare(`INSERT INTO examples(component_id,yaml) VALUES(?,?)`)
+    if err != nil { return err }
+    defer exStmt.Close()
+
+    sigStmt, err := tx.Prepare(`INSERT INTO component_signals(component_id,signal) VALUES(?,?)`)
+    if err != nil { return err }
+    defer sigStmt.Close()
+
+    pairStmt, err := tx.Prepare(`INSERT INTO connector_signal_pairs(component_id,from_signal,to_signal) VALUES(?,?,?)`)
+    if err != nil { return err }
+    defer pairStmt.Close()
+
+    stabStmt, err := tx.Prepare(`INSERT INTO component_stability(component_id,signal,level) VALUES(?,?,?)`)
+    if err != nil { return err }
+    defer stabStmt.Close()
+
+    distStmt, err := tx.Prepare(`INSERT INTO component_distributions(component_id,distribution) VALUES(?,?)`)
+    if err != nil { return err }
+    defer distStmt.Close()
+    releaseStmt, err := tx.Prepare(`INSERT INTO component_releases(component_id,distribution) VALUES(?,?)`)
+    if err != nil { return err }
+    defer releaseStmt.Close()
+
+    ownerStmt, err := tx.Prepare(`INSERT INTO component_codeowners(component_id,owner) VALUES(?,?)`)
+    if err != nil { return err }
+    defer ownerStmt.Close()
+
+    metricStmt, err := tx.Prepare(`INSERT INTO emitted_metrics(component_id,name,description,unit,metric_type,enabled) VALUES(?,?,?,?,?,?)`)
+    if err != nil { return err }
+    defer metricStmt.Close()
+
+    metricAttrStmt, err := tx.Prepare(`INSERT INTO emitted_metric_attributes(metric_id,idx,name,description,type,enum_json) VALUES(?,?,?,?,?,?)`)
+    if err != nil { return err }
+    defer metricAttrStmt.Close()
+
+    searchStmt, err := tx.Prepare(`INSERT INTO search_index(version,component_id,field_id,component,field,path,description) VALUES(?,?,?,?,?,?,?)`)
+    if err != nil { return err }
+    defer searchStmt.Close()
+
+    resAttrStmt, err := tx.Prepare(`INSERT INTO resource_attributes(component_id,name,description,type,enum_json,enabled) VALUES(?,?,?,?,?,?)`)
+    if err != nil { return err }
+    defer resAttrStmt.Close()
+
+    // insertField stores a field row owned by a component or a definition (the other id nil);
+    // refID/refPath tie a component's override (stored with absolute paths) to the definition
+    // field it replaces
+    insertField := func(f Field, componentID, definitionID, refID any, refPath any, tokens, declared []string) (int64, error) {
+        var declaredJSON any
+        if f.DeclaredIn != "" {
+            if declared == nil { declared = []string{} }
+            declaredJSON = mustJSON(declared)
+        }
+        res, err := fieldStmt.Exec(componentID, definitionID, refID, refPath, f.Name, f.Type, btoi(f.Required), mustJSON(f.Default), nullIfEmpty(f.Description), nullIfEmpty(f.Format), nullIfEmpty(f.Unit), btoi(f.Sensitive), nullIfEmpty(f.ItemType), nullIfEmpty(f.RefKind), nullIfEmpty(f.RefScope), mustJSON(f.Validation), btoi(f.CustomUnmarshal), btoi(f.Deprecated), nullIfEmpty(f.Replacement), nullIfEmpty(f.DeclaredIn), declaredJSON)
+        if err != nil { return 0, err }
+        fieldID, err := res.LastInsertId()
+        if err != nil { return 0, err }
+        for i, t := range tokens {
+            if _, err := pathStmt.Exec(fieldID, i, t); err != nil { return 0, err }
+        }
+        for _, ev := range f.EnumValues {
+            if _, err := enumStmt.Exec(fieldID, ev); err != nil { return 0, err }
+        }
+        return fieldID, nil
+    }
+
+    definitionIDs := map[string]int64{}
+    definitionFieldIDs := map[string][]int64{} // definition key -> row ids of its fields, in order
+    defKeys := make([]string, 0, len(d.Definitions))
+    for key := range d.Definitions { defKeys = append(defKeys, key) }
+    sort.Strings(defKeys)
+    for _, key := range defKeys {
+        def := d.Definitions[key]
+        res, err := defStmt.Exec(d.Version, key, def.Package, def.Type)
+        if err != nil { return err }
+        definitionID, err := res.LastInsertId()
+        if err != nil { return err }
+        definitionIDs[key] = definitionID
+        for _, f := range def.Fields {
+            fieldID, err := insertField(f, nil, definitionID, nil, strings.Join(f.PathTokens, "."), f.PathTokens, f.DeclaredPath)
+            if err != nil { return err }
+            definitionFieldIDs[key] = append(definitionFieldIDs[key], fieldID)
+        }
+    }
+
+    componentIDs := map[string]int64{} // <type>/<name> -> row id, for feature gate links
+    rows := d.Components
+    if d.Service != nil { rows = append(append([]Component{}, rows...), *d.Service) }
+    for _, c := range rows {
+        res, err := compStmt.Exec(c.Name, c.Type, nullIfEmpty(c.Description), d.Version, btoi(c.CustomUnmarshal), nullIfEmpty(c.Module))
+        if err != nil { return err }
+        componentID, err := res.LastInsertId()
+        if err != nil { return err }
+        componentIDs[c.Type+"/"+c.Name] = componentID
+        if _, err := searchStmt.Exec(d.Version, componentID, nil, c.Type+" "+c.Name, "", "", c.Description); err != nil { return err }
+        // Fields; a "$ref" field becomes a component_definitions row at the position of the
+        // component's own fields it precedes, plus one row per override
+        own := 0
+        for _, f := range c.Config.Fields {
+            if f.Ref == "" {
+                fieldID, err := insertField(f, componentID, nil, nil, nil, f.PathTokens, f.DeclaredPath)
+                if err != nil { return err }
+                if _, err := searchStmt.Exec(d.Version, componentID, fieldID, c.Type+" "+c.Name, f.Name, strings.Join(f.PathTokens, "."), f.Description); err != nil { return err }
+                own++
+                continue
+            }
+            definitionID, ok := definitionIDs[f.Ref]
+            if !ok { return fmt.Errorf("%s/%s: unknown definition %q", c.Type, c.Name, f.Ref) }
+            prefix := f.PathTokens
+            if prefix == nil { prefix = []string{} }
+            res, err := refStmt.Exec(componentID, definitionID, own, mustJSON(prefix), nullIfEmpty(enumJSON(f.Omit)))
+            if err != nil { return err }
+            refID, err := res.LastInsertId()
+            if err != nil { return err }
+            skip := map[string]bool{}
+            for _, k := range f.Omit { skip[k] = true }
+            for _, o := range f.Overrides {
+                skip[strings.Join(o.PathTokens, ".")] = true
+                tokens := append(append([]string{}, prefix...), o.PathTokens...)
+                declared := append(append([]string{}, prefix...), o.DeclaredPath...)
+                fieldID, err := insertField(o, componentID, nil, refID, strings.Join(o.PathTokens, "."), tokens, declared)
+                if err != nil { return err }
+                if _, err := searchStmt.Exec(d.Version, componentID, fieldID, c.Type+" "+c.Name, o.Name, strings.Join(tokens, "."), o.Description); err != nil { return err }
+            }
+            // The shared fields are searched as this component's, under its path
+            for i, df := range d.Definitions[f.Ref].Fields {
+                if skip[strings.Join(df.PathTokens, ".")] { continue }
+                path := strings.Join(append(append([]string{}, prefix...), df.PathTokens...), ".")
+                if _, err := searchStmt.Exec(d.Version, componentID, definitionFieldIDs[f.Ref][i], c.Type+" "+c.Name, df.Name, path, df.Description); err != nil { return err }
+            }
+        }
+        // Constraints
+        for _, cs := range c.Constraints {
+            keysJSON := mustJSON(cs.KeyTokens)
+            if _, err := consStmt.Exec(componentID, cs.Kind, keysJSON, nullIfEmpty(cs.Message)); err != nil { return err }
+        }
+        // Examples
+        for _, ex := range c.Config.Examples {
+            if strings.TrimSpace(ex) == "" { continue }
+            if _, err := exStmt.Exec(componentID, ex); err != nil { return err }
+        }
+        // Supported signals / connector pairs
+        for _, sg := range c.Signals {
+            if _, err := sigStmt.Exec(componentID, sg); err != nil { return err }
+        }
+        for _, p := range c.SignalPairs {
+            if _, err := pairStmt.Exec(componentID, p.From, p.To); err != nil { return err }
+        }
+        for sg, level := range c.Stability {
+            if _, err := stabStmt.Exec(componentID, sg, level); err != nil { return err }
+        }
+        // metadata.yaml
+        for _, dist := range c.Distributions {
+            if _, err := distStmt.Exec(componentID, dist); err != nil { return err }
+        }
+        for _, dist := range c.Releases {
+            if _, err := releaseStmt.Exec(componentID, dist); err != nil { return err }
+        }
+        for _, owner := range c.Codeowners {
+            if _, err := ownerStmt.Exec(componentID, owner); err != nil { return err }
+        }
+        for _, m := range c.Metrics {
+            res, err := metricStmt.Exec(componentID, m.Name, nullIfEmpty(m.Description), nullIfEmpty(m.Unit), nullIfEmpty(m.Type), btoi(m.Enabled))
+            if err != nil { return err }
+            metricID, err := res.LastInsertId()
+            if err != nil { return err }
+            for i, a := range m.Attributes {
+                if _, err := metricAttrStmt.Exec(metricID, i, a.Name, nullIfEmpty(a.Description), nullIfEmpty(a.Type), nullIfEmpty(enumJSON(a.Enum))); err != nil { return err }
+            }
+        }
+        for _, a := range c.ResourceAttributes {
+            if _, err := resAttrStmt.Exec(componentID, a.Name, nullIfEmpty(a.Description), nullIfEmpty(a.Type), nullIfEmpty(enumJSON(a.Enum)), btoi(a.Enabled)); err != nil { return err }
+        }
+    }
+
+    for _, g := range d.FeatureGates {
+        res, err := tx.Exec(`INSERT INTO feature_gates(version,gate_id,stage,description,reference_url,from_version,to_version,package) VALUES(?,?,?,?,?,?,?,?)`,
+            d.Version, g.ID, g.Stage, nullIfEmpty(g.Description), nullIfEmpty(g.ReferenceURL), nullIfEmpty(g.FromVersion), nullIfEmpty(g.ToVersion), g.Package)
+        if err != nil { return err }
+        gateID, err := res.LastInsertId()
+        if err != nil { return err }
+        for _, key := range g.Components {
+            componentID, ok := componentIDs[key]
+            if !ok { continue }
+            if _, err := tx.Exec(`INSERT OR IGNORE INTO component_feature_gates(component_id,gate_id) VALUES(?,?)`, componentID, gateID); err != nil { return err }
+        }
+    }
+
+    return tx.Commit()
+}
+
+// updateMeta renumbers the versions table in release order and points
+// meta.collector_version at the newest version present in the database.
+func updateMeta(db *sql.DB) error {
+    rows, err := db.Query(`SELECT version FROM versions`)
+    if err != nil { return err }
+    var versions []string
+    for rows.Next() {
+        var v string
+        if err := rows.Scan(&v); err != nil { rows.Close(); return err }
+        versions = append(versions, v)
+    }
+    rows.Close()
+    if err := rows.Err(); err != nil { return err }
+    if len(versions) == 0 { return nil }
+    sort.SliceStable(versions, func(i, j int) bool { return compareVersions(versions[i], versions[j]) < 0 })
+
+    tx, err := db.Begin()
+    if err != nil { return err }
+    defer func() { _ = tx.Rollback() }()
+    for i, v := range versions {
+        if _, err := tx.Exec(`UPDATE versions SET ordinal = ? WHERE version = ?`, i, v); err != nil { return err }
+    }
+    versionsJSON, _ := json.Marshal(versions)
+    if _, err := tx.Exec(`INSERT OR REPLACE INTO meta(key,value) VALUES
+        ('collector_version', ?),
+        ('collector_versions', ?),
+        ('schema_version', ?)
+    ;`, versions[len(versions)-1], string(versionsJSON), schemaVersion); err != nil { return err }
+    return tx.Commit()
+}
+
+// SearchResult is one ranked --search hit: a component (Path empty), one of its fields or a
+// field of a shared definition.
+type SearchResult struct {
+    Component   string
+    Path        string
+    Kind        string
+    Description string
+}
+
+// SearchComponents runs a ranked full-text query against search_index. Every word must
+// match (as a prefix) unless that finds nothing, in which case any word may match.
+// Field names and paths weigh more than component names, descriptions least.
+func SearchComponents(db *sql.DB, version, query string, limit int) ([]SearchResult, error) {
+    if version == "" {
+        if err := db.QueryRow(`SELECT value FROM meta WHERE key = 'collector_version'`).Scan(&version); err != nil {
+            return nil, fmt.Errorf("no collector version in database: %v", err)
+        }
+    }
+    var terms []string
+    for _, w := range strings.FieldsFunc(strings.ToLower(query), func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+        terms = append(terms, `"`+w+`"*`)
+    }
+    if len(terms) == 0 { return nil, fmt.Errorf("empty query") }
+    for _, op := range []string{" ", " OR "} {
+        rows, err := db.Query(`SELECT COALESCE(c.type,''), COALESCE(c.name,''), s.component, s.path, COALESCE(f.kind,''), s.description
+            FROM search_index s
+            LEFT JOIN components c ON c.id = s.component_id
+            LEFT JOIN fields f ON f.id = s.field_id
+            WHERE search_index MATCH ? AND s.version = ?
+            ORDER BY bm25(search_index, 0, 0, 0, 2.0, 4.0, 4.0, 1.0)
+            LIMIT ?`, strings.Join(terms, op), version, limit)
+        if err != nil { return nil, err }
+        var out []SearchResult
+        for rows.Next() {
+            var r SearchResult
+            var typ, name string
+            if err := rows.Scan(&typ, &name, &r.Component, &r.Path, &r.Kind, &r.Description); err != nil { rows.Close(); return nil, err }
+            if typ != "" { r.Component = typ + "/" + name }
+            if typ != "" && typ == name { r.Component = name }
+            out = append(out, r)
+        }
+        rows.Close()
+        if err := rows.Err(); err != nil { return nil, err }
+        if len(out) > 0 || len(terms) == 1 { return out, nil }
+    }
+    return nil, nil
+}
+
+// sentenceEnd finds the end of a description's first sentence ("e.g. foo" does not end one).
+var sentenceEnd = regexp.MustCompile(`\.\s+[A-Z]`)
+
+// PrintSearchResults writes one aligned line per hit with the first sentence of its description.
+func PrintSearchResults(w io.Writer, results []SearchResult) {
+    tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+    for _, r := range results {
+        desc := r.Description
+        if loc := sentenceEnd.FindStringIndex(desc); loc != nil { desc = desc[:loc[0]+1] }
+        kind := r.Kind
+        if r.Path == "" { kind = "(component)" }
+        fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Component, r.Path, kind, desc)
+    }
+    _ = tw.Flush()
+}
+
+// ExportVersion rebuilds the extractor document for one stored version so JSON-based
+// tooling (e.g. the parse-otelcol diff command) can compare versions kept in the database.
+func ExportVersion(db *sql.DB, version string) (*Extracted, error) {
+    d := &Extracted{Version: version, Components: []Component{}}
+    var sec, sig, pipe, levels, refs string
+    err := db.QueryRow(`SELECT sections_json,signals_json,pipeline_shape_json,telemetry_levels_json,default_level,COALESCE(pipeline_refs_json,'') FROM document WHERE version = ?`, version).
+        Scan(&sec, &sig, &pipe, &levels, &d.Document.Telemetry.DefaultLevel, &refs)
+    if err == sql.ErrNoRows { return nil, fmt.Errorf("version not found") }
+    if err != nil { return nil, err }
+    for _, col := range []struct {
+        name, data string
+        dst        any
+    }{
+        {"sections_json", sec, &d.Document.Sections},
+        {"signals_json", sig, &d.Document.Signals},
+        {"pipeline_shape_json", pipe, &d.Document.PipelineShape},
+        {"telemetry_levels_json", levels, &d.Document.Telemetry.MetricsLevels},
+        {"pipeline_refs_json", refs, &d.Document.PipelineRefs},
+    } {
+        // pipeline_refs_json is NULL in databases built before pipeline refs were recorded
+        if col.data == "" && col.name == "pipeline_refs_json" { continue }
+        if err := json.Unmarshal([]byte(col.data), col.dst); err != nil { return nil, fmt.Errorf("document.%s: %v", col.name, err) }
+    }
+
+    rows, err := db.Query(`SELECT id,name,type,COALESCE(description,''),custom_unmarshal,COALESCE(module,'') FROM components WHERE version = ? ORDER BY id`, version)
+    if err != nil { return nil, err }
+    var ids []int64
+    for rows.Next() {
+        var id int64
+        var c Component
+        var custom int
+        if err := rows.Scan(&id, &c.Name, &c.Type, &c.Description, &custom, &c.Module); err != nil { rows.Close(); return nil, err }
+        c.CustomUnmarshal = custom != 0
+        c.Config.Fields = []Field{}
+        c.Constraints = []Constraint{}
+        ids = append(ids, id)
+        d.Components = append(d.Components, c)
+    }
+    rows.Close()
+    if err := rows.Err(); err != nil { return nil, err }
+
+    for i, id := range ids {
+        c := &d.Components[i]
+        if err := exportFields(db, id, c); err != nil { return nil, err }
+        cons, err := db.Query(`SELECT kind,keys_json,COALESCE(message,'') FROM constraints WHERE component_id = ? ORDER BY id`, id)
+        if err != nil { return nil, err }
+        for cons.Next() {
+            var cs Constraint
+            var keys string
+            if err := cons.Scan(&cs.Kind, &keys, &cs.Message); err != nil { cons.Close(); return nil, err }
+            if err := json.Unmarshal([]byte(keys), &cs.KeyTokens); err != nil { cons.Close(); return nil, fmt.Errorf("%s/%s constraint keys: %v", c.Type, c.Name, err) }
+            c.Constraints = append(c.Constraints, cs)
+        }
+        cons.Close()
+        exs, err := db.Query(`SELECT yaml FROM examples WHERE component_id = ? ORDER BY id`, id)
+        if err != nil { return nil, err }
+        for exs.Next() {
+            var y string
+            if err := exs.Scan(&y); err != nil { exs.Close(); return nil, err }
+            c.Config.Examples = append(c.Config.Examples, y)
+        }
+        exs.Close()
+        sigs, err := db.Query(`SELECT signal FROM component_signals WHERE component_id = ? ORDER BY signal`, id)
+        if err != nil { return nil, err }
+        for sigs.Next() {
+            var sg string
+            if err := sigs.Scan(&sg); err != nil { sigs.Close(); return nil, err }
+            c.Signals = append(c.Signals, sg)
+        }
+        sigs.Close()
+        pairs, err := db.Query(`SELECT from_signal,to_signal FROM connector_signal_pairs WHERE component_id = ? ORDER BY from_signal,to_signal`, id)
+        if err != nil { return nil, err }
+        for pairs.Next() {
+            var p SignalPair
+            if err := pairs.Scan(&p.From, &p.To); err != nil { pairs.Close(); return nil, err }
+            c.SignalPairs = append(c.SignalPairs, p)
+        }
+        pairs.Close()
+        stab, err := db.Query(`SELECT signal,level FROM component_stability WHERE component_id = ?`, id)
+        if err != nil { return nil, err }
+        for stab.Next() {
+            var sg, level string
+            if err := stab.Scan(&sg, &level); err != nil { stab.Close(); return nil, err }
+            if c.Stability == nil { c.Stability = map[string]string{} }
+            c.Stability[sg] = level
+        }
+        stab.Close()
+        if err := exportMetadata(db, id, c); err != nil { return nil, err }
+        rels, err := db.Query(`SELECT distribution FROM component_releases WHERE component_id = ? ORDER BY distribution`, id)
+        if err != nil { return nil, err }
+        for rels.Next() {
+            var v string
+            if err := rels.Scan(&v); err != nil { rels.Close(); return nil, err }
+            c.Releases = append(c.Releases, v)
+        }
+        rels.Close()
+    }
+    // The distributions read at extraction time are the ones shipping at least one component
+    rels, err := db.Query(`SELECT DISTINCT r.distribution FROM component_releases r JOIN components c ON c.id = r.component_id WHERE c.version = ? ORDER BY r.distribution`, version)
+    if err != nil { return nil, err }
+    for rels.Next() {
+        var v string
+        if err := rels.Scan(&v); err != nil { rels.Close(); return nil, err }
+        d.Releases = append(d.Releases, v)
+    }
+    rels.Close()
+    defs, err := db.Query(`SELECT id,def_key,package,type FROM definitions WHERE version = ? ORDER BY id`, version)
+    if err != nil { return nil, err }
+    var defIDs []int64
+    var defKeys []string
+    var defList []Definition
+    for defs.Next() {
+        var id int64
+        var key string
+        var def Definition
+        if err := defs.Scan(&id, &key, &def.Package, &def.Type); err != nil { defs.Close(); return nil, err }
+        defIDs, defKeys, defList = append(defIDs, id), append(defKeys, key), append(defList, def)
+    }
+    defs.Close()
+    if err := defs.Err(); err != nil { return nil, err }
+    for i, id := range defIDs {
+        fields, err := queryFields(db, `definition_id = ?`, id)
+        if err != nil { return nil, err }
+        defList[i].Fields = fields
+        if d.Definitions == nil { d.Definitions = map[string]Definition{} }
+        d.Definitions[defKeys[i]] = defList[i]
+    }
+    for i := range d.Components {
+        if d.Components[i].Type != "service" { continue }
+        svc := d.Components[i]
+        d.Service = &svc
+        d.Components = append(d.Components[:i], d.Components[i+1:]...)
+        break
+    }
+    gates, err := exportFeatureGates(db, version)
+    if err != nil { return nil, err }
+    d.FeatureGates = gates
+    return d, nil
+}
+
+// exportFeatureGates reads back a version's feature gates with their component links.
+func exportFeatureGates(db *sql.DB, version string) ([]FeatureGate, error) {
+    rows, err := db.Query(`SELECT id,gate_id,stage,COALESCE(description,''),COALESCE(reference_url,''),COALESCE(from_version,''),COALESCE(to_version,''),package
+        FROM feature_gates WHERE version = ? ORDER BY id`, version)
+    if err != nil { return nil, err }
+    var ids []int64
+    var gates []FeatureGate
+    for rows.Next() {
+        var id int64
+        var g FeatureGate
+        if err := rows.Scan(&id, &g.ID, &g.Stage, &g.Description, &g.ReferenceURL, &g.FromVersion, &g.ToVersion, &g.Package); err != nil { rows.Close(); return nil, err }
+        ids = append(ids, id)
+        gates = append(gates, g)
+    }
+    rows.Close()
+    if err := rows.Err(); err != nil { return nil, err }
+    for i, id := range ids {
+        comps, err := db.Query(`SELECT c.type || '/' || c.name FROM component_feature_gates cf JOIN components c ON c.id = cf.component_id
+            WHERE cf.gate_id = ? ORDER BY 1`, id)
+        if err != nil { return nil, err }
+        for comps.Next() {
+            var key string
+            if err := comps.Scan(&key); err != nil { comps.Close(); return nil, err }
+            gates[i].Components = append(gates[i].Components, key)
+        }
+        comps.Close()
+    }
+    return gates, nil
+}
+
+// exportMetadata reads back the metadata.yaml tables for one component.
+func exportMetadata(db *sql.DB, componentID int64, c *Component) error {
+    dists, err := db.Query(`SELECT distribution FROM component_distributions WHERE component_id = ? ORDER BY distribution`, componentID)
+    if err != nil { return err }
+    for dists.Next() {
+        var v string
+        if err := dists.Scan(&v); err != nil { dists.Close(); return err }
+        c.Distributions = append(c.Distributions, v)
+    }
+    dists.Close()
+    owners, err := db.Query(`SELECT owner FROM component_codeowners WHERE component_id = ? ORDER BY rowid`, componentID)
+    if err != nil { return err }
+    for owners.Next() {
+        var v string
+        if err := owners.Scan(&v); err != nil { owners.Close(); return err }
+        c.Codeowners = append(c.Codeowners, v)
+    }
+    owners.Close()
+
+    metrics, err := db.Query(`SELECT id,name,COALESCE(description,''),COALESCE(unit,''),COALESCE(metric_type,''),enabled FROM emitted_metrics WHERE component_id = ? ORDER BY id`, componentID)
+    if err != nil { return err }
+    var metricIDs []int64
+    for metrics.Next() {
+        var id int64
+        var m EmittedMetric
+        var enabled int
+        if err := metrics.Scan(&id, &m.Name, &m.Description, &m.Unit, &m.Type, &enabled); err != nil { metrics.Close(); return err }
+        m.Enabled = enabled != 0
+        metricIDs = append(metricIDs, id)
+        c.Metrics = append(c.Metrics, m)
+    }
+    metrics.Close()
+    for i, id := range metricIDs {
+        m := &c.Metrics[i]
+        attrs, err := db.Query(`SELECT name,COALESCE(description,''),COALESCE(type,''),COALESCE(enum_json,'') FROM emitted_metric_attributes WHERE metric_id = ? ORDER BY idx`, id)
+        if err != nil { return err }
+        for attrs.Next() {
+            var a MetricAttribute
+            var enum string
+            if err := attrs.Scan(&a.Name, &a.Description, &a.Type, &enum); err != nil { attrs.Close(); return err }
+            if enum != "" {
+                if err := json.Unmarshal([]byte(enum), &a.Enum); err != nil { attrs.Close(); return fmt.Errorf("metric %s attribute %s enum: %v", m.Name, a.Name, err) }
+            }
+            m.Attributes = append(m.Attributes, a)
+        }
+        attrs.Close()
+    }
+
+    res, err := db.Query(`SELECT name,COALESCE(description,''),COALESCE(type,''),COALESCE(enum_json,''),enabled FROM resource_attributes WHERE component_id = ? ORDER BY rowid`, componentID)
+    if err != nil { return err }
+    for res.Next() {
+        var a ResourceAttribute
+        var enum string
+        var enabled int
+        if err := res.Scan(&a.Name, &a.Description, &a.Type, &enum, &enabled); err != nil { res.Close(); return err }
+        if enum != "" {
+            if err := json.Unmarshal([]byte(enum), &a.Enum); err != nil { res.Close(); return fmt.Errorf("resource attribute %s enum: %v", a.Name, err) }
+        }
+        a.Enabled = enabled != 0
+        c.ResourceAttributes = append(c.ResourceAttributes, a)
+    }
+    res.Close()
+    return nil
+}
+
+// exportFields rebuilds a component's field list, turning component_definitions rows back
+// into "$ref" fields carrying their overrides.
+func exportFields(db *sql.DB, componentID int64, c *Component) error {
+    own, err := queryFields(db, `component_id = ? AND ref_id IS NULL`, componentID)
+    if err != nil { return err }
+    rows, err := db.Query(`SELECT r.id,d.def_key,d.type,r.position,r.path_json,COALESCE(r.omit_json,'')
+        FROM component_definitions r JOIN definitions d ON d.id = r.definition_id WHERE r.component_id = ? ORDER BY r.position,r.id`, componentID)
+    if err != nil { return err }
+    var refIDs []int64
+    var positions []int
+    var refs []Field
+    for rows.Next() {
+        var id int64
+        var pos int
+        var path, omit string
+        f := Field{Type: "object"}
+        if err := rows.Scan(&id, &f.Ref, &f.Name, &pos, &path, &omit); err != nil { rows.Close(); return err }
+        if err := json.Unmarshal([]byte(path), &f.PathTokens); err != nil { rows.Close(); return fmt.Errorf("%s path: %v", f.Ref, err) }
+        if omit != "" {
+            if err := json.Unmarshal([]byte(omit), &f.Omit); err != nil { rows.Close(); return fmt.Errorf("%s omit: %v", f.Ref, err) }
+        }
+        refIDs, positions, refs = append(refIDs, id), append(positions, pos), append(refs, f)
+    }
+    rows.Close()
+    if err := rows.Err(); err != nil { return err }
+    for i, id := range refIDs {
+        overrides, err := queryFields(db, `ref_id = ?`, id)
+        if err != nil { return err }
+        for j := range overrides {
+            o := &overrides[j]
+            o.PathTokens = o.PathTokens[len(refs[i].PathTokens):]
+            if len(o.PathTokens) == 0 { o.PathTokens = nil }
+            if len(o.DeclaredPath) >= len(refs[i].PathTokens) { o.DeclaredPath = o.DeclaredPath[len(refs[i].PathTokens):] }
+            if len(o.DeclaredPath) == 0 { o.DeclaredPath = nil }
+        }
+        refs[i].Overrides = overrides
+        if len(refs[i].PathTokens) == 0 { refs[i].PathTokens = nil }
+    }
+    next := 0
+    for i, f := range own {
+        for next < len(refs) && positions[next] <= i {
+            c.Config.Fields = append(c.Config.Fields, refs[next])
+            next++
+        }
+        c.Config.Fields = append(c.Config.Fields, f)
+    }
+    c.Config.Fields = append(c.Config.Fields, refs[next:]...)
+    return nil
+}
+
+// queryFields reads the field rows matching where (with their paths and enum values) in id order.
+func queryFields(db *sql.DB, where string, arg any) ([]Field, error) {
+    rows, err := db.Query(`SELECT id,name,kind,required,COALESCE(default_json,''),COALESCE(description,''),COALESCE(format,''),COALESCE(unit,''),sensitive,
+        COALESCE(item_type,''),COALESCE(ref_kind,''),COALESCE(ref_scope,''),COALESCE(validation_json,''),custom_unmarshal,deprecated,COALESCE(replacement,''),COALESCE(declared_in,''),COALESCE(declared_path_json,'') FROM fields WHERE `+where+` ORDER BY id`, arg)
+    if err != nil { return nil, err }
+    var ids []int64
+    var fields []Field
+    for rows.Next() {
+        var id int64
+        var f Field
+        var required, sensitive, custom, deprecated int
+        var def, val, declared string
+        if err := rows.Scan(&id, &f.Name, &f.Type, &required, &def, &f.Description, &f.Format, &f.Unit, &sensitive, &f.ItemType, &f.RefKind, &f.RefScope, &val, &custom, &deprecated, &f.Replacement, &f.DeclaredIn, &declared); err != nil {
+            rows.Close()
+            return nil, err
+        }
+        f.Required = required != 0
+        f.Sensitive = sensitive != 0
+        f.CustomUnmarshal = custom != 0
+        f.Deprecated = deprecated != 0
+        for _, col := range []struct {
+            name, data string
+            dst        any
+        }{{"default_json", def, &f.Default}, {"validation_json", val, &f.Validation}, {"declared_path_json", declared, &f.DeclaredPath}} {
+            if col.data == "" || (col.name == "validation_json" && col.data == "{}") { continue }
+            if err := json.Unmarshal([]byte(col.data), col.dst); err != nil {
+                rows.Close()
+                return nil, fmt.Errorf("field %d %s: %v", id, col.name, err)
+            }
+        }
+        if len(f.DeclaredPath) == 0 { f.DeclaredPath = nil }
+        ids = append(ids, id)
+        fields = append(fields, f)
+    }
+    rows.Close()
+    if err := rows.Err(); err != nil { return nil, err }
+    for i, id := range ids {
+        f := &fields[i]
+        toks, err := db.Query(`SELECT token FROM field_paths WHERE field_id = ? ORDER BY idx`, id)
+        if err != nil { return nil, err }
+        for toks.Next() {
+            var t string
+            if err := toks.Scan(&t); err != nil { toks.Close(); return nil, err }
+            f.PathTokens = append(f.PathTokens, t)
+        }
+        toks.Close()
+        enums, err := db.Query(`SELECT value FROM field_enums WHERE field_id = ? ORDER BY rowid`, id)
+        if err != nil { return nil, err }
+        for enums.Next() {
+            var v string
+            if err := enums.Scan(&v); err != nil { enums.Close(); return nil, err }
+            f.EnumValues = append(f.EnumValues, v)
+        }
+        enums.Close()
+    }
+    return fields, nil
+}
+
+func mustJSON(v any) string {
+    if v == nil { return "" }
+    // Avoid encoding empty maps/slices as "null"; prefer empty literal
+    switch t := v.(type) {
+    case map[string]string:
+        if len(t) == 0 { return "{}" }
+    }
+    b, err := json.Marshal(v)
+    if err != nil { return "" }
+    return string(b)
+}
+
+func enumJSON(values []string) string {
+    if len(values) == 0 { return "" }
+    return mustJSON(values)
+}
+
+func btoi(b bool) int { if b { return 1 }; return 0 }
+
+func nullIfEmpty(s string) any { if strings.TrimSpace(s) == "" { return nil }; return s }
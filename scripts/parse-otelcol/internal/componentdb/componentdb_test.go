@@ -0,0 +1,176 @@
+package componentdb
+
+import (
+    "database/sql"
+    "encoding/json"
+    "flag"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    _ "modernc.org/sqlite"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite testdata/golden from the current output")
+
+// fixtureGlob matches two extractor documents of the parse-otelcol fixture collector:
+// v0.2.0 drops exporter/retry and rewords receiver/squash.
+const fixtureGlob = "testdata/configs_*.json"
+
+// fixtureDocs reads both fixture versions, oldest first.
+func fixtureDocs(t *testing.T) []*Extracted {
+    t.Helper()
+    docs, err := ReadDocuments(fixtureGlob)
+    if err != nil { t.Fatal(err) }
+    if len(docs) != 2 || docs[0].Version != "v0.1.0" || docs[1].Version != "v0.2.0" { t.Fatalf("fixture versions out of order: %v", docs) }
+    return docs
+}
+
+// fixtureDoc returns the fixture document of version.
+func fixtureDoc(t *testing.T, version string) *Extracted {
+    t.Helper()
+    for _, d := range fixtureDocs(t) {
+        if d.Version == version { return d }
+    }
+    t.Fatalf("no fixture version %s", version)
+    return nil
+}
+
+// openDB opens a fresh database file, closed again when the test ends.
+func openDB(t *testing.T) *sql.DB {
+    t.Helper()
+    db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "components.db"))
+    if err != nil { t.Fatal(err) }
+    t.Cleanup(func() { db.Close() })
+    return db
+}
+
+// fixtureDB builds a database holding both fixture versions.
+func fixtureDB(t *testing.T) *sql.DB {
+    t.Helper()
+    db := openDB(t)
+    if err := Build(db, fixtureDocs(t), false); err != nil { t.Fatal(err) }
+    return db
+}
+
+func queryString(t *testing.T, db *sql.DB, query string, args ...any) string {
+    t.Helper()
+    var s string
+    if err := db.QueryRow(query, args...).Scan(&s); err != nil { t.Fatalf("%s: %v", query, err) }
+    return s
+}
+
+// versionOrder lists the stored versions as version:ordinal in ordinal order.
+func versionOrder(t *testing.T, db *sql.DB) string {
+    t.Helper()
+    return queryString(t, db, `SELECT group_concat(version || ':' || ordinal, ',') FROM (SELECT version, ordinal FROM versions ORDER BY ordinal)`)
+}
+
+func TestBuildVersions(t *testing.T) {
+    docs := fixtureDocs(t)
+    db := openDB(t)
+    if err := Build(db, docs, false); err != nil { t.Fatal(err) }
+    if got := versionOrder(t, db); got != "v0.1.0:0,v0.2.0:1" { t.Errorf("versions = %s", got) }
+    components := func(version string) string {
+        t.Helper()
+        return queryString(t, db, `SELECT COUNT(*) FROM components WHERE version = ? AND type != 'service'`, version)
+    }
+    if got := components("v0.1.0"); got != "10" { t.Errorf("v0.1.0 components = %s, want 10", got) }
+    if got := components("v0.2.0"); got != "9" { t.Errorf("v0.2.0 components = %s, want 9", got) }
+
+    // Loading a version again replaces its rows (dependent rows cascade) and leaves the other
+    // versions alone
+    reload := *docs[0]
+    reload.Components = nil
+    for _, c := range docs[0].Components {
+        if c.Name != "squash" { reload.Components = append(reload.Components, c) }
+    }
+    if err := Build(db, []*Extracted{&reload}, true); err != nil { t.Fatal(err) }
+    if got := components("v0.1.0"); got != "9" { t.Errorf("reloaded v0.1.0 components = %s, want 9", got) }
+    if got := components("v0.2.0"); got != "9" { t.Errorf("v0.2.0 components after reloading v0.1.0 = %s, want 9", got) }
+    for _, q := range []string{
+        `SELECT COUNT(*) FROM fields WHERE component_id IS NOT NULL AND component_id NOT IN (SELECT id FROM components)`,
+        `SELECT COUNT(*) FROM emitted_metrics WHERE component_id NOT IN (SELECT id FROM components)`,
+        `SELECT COUNT(*) FROM search_index WHERE component_id NOT IN (SELECT id FROM components)`,
+    } {
+        if got := queryString(t, db, q); got != "0" { t.Errorf("%s = %s, want 0", q, got) }
+    }
+    if got := queryString(t, db, `SELECT COUNT(*) FROM definitions WHERE version = 'v0.1.0'`); got != "6" { t.Errorf("v0.1.0 definitions = %s, want 6", got) }
+    if got := queryString(t, db, `SELECT COUNT(*) FROM feature_gates WHERE version = 'v0.1.0'`); got != "2" { t.Errorf("v0.1.0 feature gates = %s, want 2", got) }
+
+    // Appended versions are renumbered in release order around the stored ones
+    older, newer := *docs[0], *docs[1]
+    older.Version, newer.Version = "v0.0.9", "v0.10.0"
+    if err := Build(db, []*Extracted{&older, &newer}, true); err != nil { t.Fatal(err) }
+    if got := versionOrder(t, db); got != "v0.0.9:0,v0.1.0:1,v0.2.0:2,v0.10.0:3" { t.Errorf("versions after append = %s", got) }
+    for key, want := range map[string]string{
+        "collector_version":  "v0.10.0",
+        "collector_versions": `["v0.0.9","v0.1.0","v0.2.0","v0.10.0"]`,
+        "schema_version":     schemaVersion,
+    } {
+        if got := queryString(t, db, `SELECT value FROM meta WHERE key = ?`, key); got != want { t.Errorf("meta.%s = %s, want %s", key, got, want) }
+    }
+}
+
+func TestAppendSchemaVersion(t *testing.T) {
+    docs := fixtureDocs(t)
+    db := openDB(t)
+    // An empty database has nothing to check
+    if err := Build(db, docs[:1], true); err != nil { t.Fatalf("append to an empty database: %v", err) }
+    if err := Build(db, docs[1:], true); err != nil { t.Fatalf("append to a current database: %v", err) }
+    if _, err := db.Exec(`UPDATE meta SET value = '1' WHERE key = 'schema_version'`); err != nil { t.Fatal(err) }
+    err := Build(db, docs[1:], true)
+    if err == nil || !strings.Contains(err.Error(), "schema version 1, this builder writes version 2") { t.Errorf("append to a version 1 database: %v", err) }
+
+    legacy := openDB(t)
+    if _, err := legacy.Exec(`CREATE TABLE components (id INTEGER PRIMARY KEY, name TEXT)`); err != nil { t.Fatal(err) }
+    err = Build(legacy, docs, true)
+    if err == nil || !strings.Contains(err.Error(), "no meta.schema_version") { t.Errorf("append to a database without meta: %v", err) }
+}
+
+func TestExportVersion(t *testing.T) {
+    db := fixtureDB(t)
+    for _, version := range []string{"v0.1.0", "v0.2.0"} {
+        doc, err := ExportVersion(db, version)
+        if err != nil { t.Fatal(err) }
+        got, err := json.MarshalIndent(doc, "", "  ")
+        if err != nil { t.Fatal(err) }
+        if version == "v0.1.0" { checkGolden(t, "testdata/golden/export_v0.1.0.json", append(got, '\n')) }
+        var names []string
+        for _, c := range doc.Components { names = append(names, c.Type+"/"+c.Name) }
+        var want []string
+        for _, c := range fixtureDoc(t, version).Components { want = append(want, c.Type+"/"+c.Name) }
+        if strings.Join(names, ",") != strings.Join(want, ",") { t.Errorf("%s components = %v, want %v", version, names, want) }
+
+        // The export is a fixpoint: loading it and exporting again changes nothing
+        again := openDB(t)
+        if err := Build(again, []*Extracted{doc}, false); err != nil { t.Fatal(err) }
+        round, err := ExportVersion(again, version)
+        if err != nil { t.Fatal(err) }
+        if data, _ := json.MarshalIndent(round, "", "  "); string(data) != string(got) { t.Errorf("%s: exporting a reloaded export differs", version) }
+    }
+    if _, err := ExportVersion(db, "v9.9.9"); err == nil || err.Error() != "version not found" { t.Errorf("unknown version: %v", err) }
+}
+
+// checkGolden compares got with the golden file at path, or rewrites it with -update.
+func checkGolden(t *testing.T, path string, got []byte) {
+    t.Helper()
+    if *updateGolden {
+        if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil { t.Fatal(err) }
+        if err := os.WriteFile(path, got, 0644); err != nil { t.Fatal(err) }
+        return
+    }
+    want, err := os.ReadFile(path)
+    if err != nil { t.Fatalf("%v (run with -update to create it)", err) }
+    if string(want) == string(got) { return }
+    wl, gl := strings.Split(string(want), "\n"), strings.Split(string(got), "\n")
+    for i := 0; i < len(wl) || i < len(gl); i++ {
+        var w, g string
+        if i < len(wl) { w = wl[i] }
+        if i < len(gl) { g = gl[i] }
+        if w != g {
+            t.Fatalf("%s differs at line %d:\n  want: %s\n   got: %s\n(run with -update to accept)", path, i+1, w, g)
+        }
+    }
+}
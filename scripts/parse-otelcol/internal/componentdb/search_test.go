@@ -0,0 +1,58 @@
+package componentdb
+
+import (
+    "bytes"
+    "strings"
+    "testing"
+)
+
+func TestSearchComponents(t *testing.T) {
+    db := fixtureDB(t)
+    // hits renders results as component:path, "-" standing for the component row itself.
+    hits := func(version, query string, limit int) string {
+        t.Helper()
+        results, err := SearchComponents(db, version, query, limit)
+        if err != nil { t.Fatalf("search %q: %v", query, err) }
+        var out []string
+        for _, r := range results {
+            path := r.Path
+            if path == "" { path = "-" }
+            out = append(out, r.Component+":"+path)
+        }
+        return strings.Join(out, ",")
+    }
+    for _, tc := range []struct {
+        name, version, query string
+        limit                int
+        want                 string
+    }{
+        {"field name", "", "insecure", 5, "exporter/constraint:insecure"},
+        {"prefix", "", "insec", 5, "exporter/constraint:insecure"},
+        // The shortest path matching the word outranks the nested ones
+        {"path rank", "", "endpoint", 2, "exporter/constraint:endpoint,receiver/optional:protocols.grpc.endpoint"},
+        // Every word must match; the description alone matches "transferred"
+        {"all words", "", "squash bytes transferred", 5, "receiver/squash:metrics.squash.bytes.enabled"},
+        // Every squash field matches through its component; the paths naming it rank first
+        {"path over component", "", "squash", 2, "receiver/squash:metrics.squash.bytes.enabled,receiver/squash:metrics.squash.errors.enabled"},
+        // No field has both words, so any word may match
+        {"any word", "", "attempts elapsed", 5, "processor/route:retry.max_attempts,processor/route:legacy_retry.max_attempts"},
+        // exporter/retry only exists in the older version; the default is the newest
+        {"newest version", "", "failure", 5, ""},
+        // Matches in the path outrank one in the description only
+        {"older version", "v0.1.0", "failure", 5, "exporter/retry:retry_on_failure.initial_interval,exporter/retry:retry_on_failure.enabled,exporter/retry:retry_on_failure.max_interval,exporter/retry:reconnect.initial_interval"},
+    } {
+        if got := hits(tc.version, tc.query, tc.limit); got != tc.want { t.Errorf("%s: search %q = %s, want %s", tc.name, tc.query, got, tc.want) }
+    }
+    if _, err := SearchComponents(db, "", " - ", 5); err == nil { t.Error("punctuation-only query: no error") }
+}
+
+func TestPrintSearchResults(t *testing.T) {
+    var b bytes.Buffer
+    PrintSearchResults(&b, []SearchResult{
+        {Component: "exporter/otlp", Description: "Exports OTLP. Supports gRPC, e.g. otlp/backup."},
+        {Component: "exporter/otlp", Path: "tls.insecure", Kind: "bool", Description: "Insecure disables TLS, e.g. for tests. Defaults to false."},
+    })
+    want := "exporter/otlp                (component)  Exports OTLP.\n" +
+        "exporter/otlp  tls.insecure  bool         Insecure disables TLS, e.g. for tests.\n"
+    if b.String() != want { t.Errorf("printed:\n%s\nwant:\n%s", b.String(), want) }
+}
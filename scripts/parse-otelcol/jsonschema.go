@@ -0,0 +1,352 @@
+package main
+
+import (
+    "encoding/json"
+    "flag"
+    "fmt"
+    "os"
+    "regexp"
+    "strconv"
+    "strings"
+)
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Go duration strings as accepted by time.ParseDuration (e.g. "1m30s", "500ms").
+const durationPattern = `^(0|[-+]?(\d+(\.\d*)?|\.\d+)(ns|us|µs|ms|s|m|h)((\d+(\.\d*)?|\.\d+)(ns|us|µs|ms|s|m|h))*)$`
+
+// host:port endpoints ("localhost:4317", ":4317", "[::1]:4317"); JSON Schema has no format for them.
+const hostPortPattern = `^([^:\[\]]*|\[[0-9A-Fa-f:.]+\]):\d+$`
+
+// confmapRefPattern matches values holding a ${env:...}, ${file:...} or ${VAR:-default}
+// reference, which the collector expands before decoding the field.
+const confmapRefPattern = `\$\{[^}]+\}`
+
+// runJSONSchema implements: jsonschema --schema=configs.json [--component=<type>/<name>] [--output=file]
+func runJSONSchema(args []string) int {
+    fs := flag.NewFlagSet("jsonschema", flag.ExitOnError)
+    schemaPath := fs.String("schema", "", "Extracted configs JSON to convert")
+    component := fs.String("component", "", "Emit only this component's schema (e.g. receiver/otlp)")
+    outPath := fs.String("output", "-", "Output file (- for stdout)")
+    fs.Usage = func() {
+        fmt.Fprintln(os.Stderr, "Usage: go run . jsonschema --schema=configs.json [--component=<type>/<name>] [--output=collector.schema.json]")
+        fs.PrintDefaults()
+    }
+    _ = fs.Parse(args)
+    if *schemaPath == "" {
+        fs.Usage()
+        return 2
+    }
+    data, err := loadExtractedData(*schemaPath)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "jsonschema: %v\n", err)
+        return 1
+    }
+    var doc map[string]any
+    if *component != "" {
+        idx := newSchemaIndex(data)
+        c := idx.components[*component]
+        if c == nil {
+            fmt.Fprintf(os.Stderr, "jsonschema: component %s not found in %s\n", *component, *schemaPath)
+            return 1
+        }
+        doc = componentJSONSchema(c)
+        doc["$schema"] = jsonSchemaDialect
+        doc["title"] = fmt.Sprintf("%s %s configuration (%s)", c.Name, c.Type, data.Version)
+    } else {
+        doc = collectorJSONSchema(data)
+    }
+    out, err := json.MarshalIndent(doc, "", "  ")
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "jsonschema: %v\n", err)
+        return 1
+    }
+    if *outPath == "-" {
+        fmt.Println(string(out))
+        return 0
+    }
+    if err := os.WriteFile(*outPath, append(out, '\n'), 0644); err != nil {
+        fmt.Fprintf(os.Stderr, "jsonschema: %v\n", err)
+        return 1
+    }
+    return 0
+}
+
+// jsonSchemaDefName is the $defs key for a component, e.g. "receiver.otlp".
+func jsonSchemaDefName(c *Component) string { return c.Type + "." + c.Name }
+
+// collectorJSONSchema builds a schema for a whole collector document: every
+// component under $defs, wired into its section by component ID pattern.
+func collectorJSONSchema(data *ExtractedData) map[string]any {
+    defs := map[string]any{}
+    bySection := map[string]map[string]any{}
+    for i := range data.Components {
+        c := &data.Components[i]
+        defs[jsonSchemaDefName(c)] = componentJSONSchema(c)
+        section := c.Type + "s"
+        if bySection[section] == nil { bySection[section] = map[string]any{} }
+        bySection[section][componentIDRegexp(data.Document, c.Name)] = map[string]any{"$ref": "#/$defs/" + jsonSchemaDefName(c)}
+    }
+
+    props := map[string]any{}
+    for _, sec := range data.Document.Sections {
+        if sec == "service" {
+            props[sec] = serviceJSONSchema(data.Document, data.Service)
+            continue
+        }
+        props[sec] = map[string]any{
+            "type":                 []string{"object", "null"},
+            "patternProperties":    orEmpty(bySection[sec]),
+            "additionalProperties": false,
+        }
+    }
+    return map[string]any{
+        "$schema":    jsonSchemaDialect,
+        "title":      fmt.Sprintf("OpenTelemetry Collector configuration (%s)", data.Version),
+        "type":       "object",
+        "properties": props,
+        "$defs":      defs,
+    }
+}
+
+func orEmpty(m map[string]any) map[string]any {
+    if m == nil { return map[string]any{} }
+    return m
+}
+
+// componentIDRegexp turns a component type into an ID pattern following DocumentSchema.ComponentIDPattern.
+func componentIDRegexp(doc DocumentSchema, typ string) string {
+    if doc.SupportsInstanceSuffix { return "^" + regexp.QuoteMeta(typ) + "(/.+)?$" }
+    return "^" + regexp.QuoteMeta(typ) + "$"
+}
+
+// serviceJSONSchema describes service.extensions, service.pipelines and service.telemetry.
+// With an extracted service schema the telemetry (and extensions) come from its fields;
+// pipelines keep the signal-keyed shape of the document schema.
+func serviceJSONSchema(doc DocumentSchema, svc *Component) map[string]any {
+    idList := map[string]any{"type": "array", "items": map[string]any{"type": "string"}}
+    pipeline := map[string]any{}
+    if doc.PipelineShape.Receivers { pipeline["receivers"] = idList }
+    if doc.PipelineShape.Processors { pipeline["processors"] = idList }
+    if doc.PipelineShape.Exporters { pipeline["exporters"] = idList }
+    quoted := make([]string, 0, len(doc.Signals))
+    for _, s := range doc.Signals { quoted = append(quoted, regexp.QuoteMeta(s)) }
+    pipelines := map[string]any{
+        "type": "object",
+        "patternProperties": map[string]any{
+            "^(" + strings.Join(quoted, "|") + ")(/.+)?$": map[string]any{
+                "type":                 "object",
+                "properties":           pipeline,
+                "additionalProperties": false,
+            },
+        },
+        "additionalProperties": false,
+    }
+    if svc != nil {
+        s := componentJSONSchema(svc)
+        props := s["properties"].(map[string]any)
+        props["pipelines"] = pipelines
+        if props["extensions"] == nil { props["extensions"] = idList }
+        return s
+    }
+    metrics := map[string]any{"type": "object"}
+    if len(doc.Telemetry.MetricsLevels) > 0 {
+        level := map[string]any{"anyOf": enumJSONSchema(doc.Telemetry.MetricsLevels)}
+        if doc.Telemetry.DefaultLevel != "" { level["default"] = doc.Telemetry.DefaultLevel }
+        metrics["properties"] = map[string]any{"level": level}
+    }
+    return map[string]any{
+        "type": "object",
+        "properties": map[string]any{
+            "extensions": idList,
+            "pipelines":  pipelines,
+            "telemetry": map[string]any{
+                "type":       "object",
+                "properties": map[string]any{"metrics": metrics},
+            },
+        },
+    }
+}
+
+// componentJSONSchema nests a component's fields by PathTokens and maps
+// component constraints to anyOf/oneOf/not-allOf groups.
+func componentJSONSchema(c *Component) map[string]any {
+    root := objectJSONSchema(buildSchemaTree(c.Config.Fields))
+    var groups []any
+    for _, cs := range c.Constraints {
+        if g := constraintJSONSchema(cs); g != nil { groups = append(groups, g) }
+    }
+    if len(groups) > 0 { root["allOf"] = groups }
+    if c.Description != "" { root["description"] = c.Description }
+    return root
+}
+
+func objectJSONSchema(n *schemaNode) map[string]any {
+    props := map[string]any{}
+    var required []string
+    for _, k := range sortedKeys(n.children) {
+        if k == "[]" || k == "{key}" { continue }
+        child := n.children[k]
+        props[k] = nodeJSONSchema(child)
+        if f := child.field; f != nil && f.Required && f.Default == nil { required = append(required, k) }
+    }
+    s := map[string]any{
+        "type":                 []string{"object", "null"},
+        "properties":           props,
+        "additionalProperties": false,
+    }
+    if values := n.children["{key}"]; values != nil { s["additionalProperties"] = nodeJSONSchema(values) }
+    if n.custom {
+        // Decoded by a custom Unmarshal: other keys or a scalar shorthand may be accepted
+        delete(s, "type")
+        s["additionalProperties"] = true
+    }
+    if len(required) > 0 { s["required"] = required }
+    return s
+}
+
+func nodeJSONSchema(n *schemaNode) map[string]any {
+    if n.field == nil {
+        if items := n.children["[]"]; items != nil && len(n.children) == 1 {
+            return map[string]any{"type": "array", "items": nodeJSONSchema(items)}
+        }
+        return objectJSONSchema(n)
+    }
+    s := fieldJSONSchema(n.field)
+    if items := n.children["[]"]; items != nil && len(items.children) > 0 && n.field.Type == "array" {
+        s["items"] = nodeJSONSchema(items)
+    }
+    if values := n.children["{key}"]; values != nil && n.field.Type == "map" {
+        s["additionalProperties"] = nodeJSONSchema(values)
+    }
+    return s
+}
+
+// fieldJSONSchema maps Type/Format/EnumValues/Default/Validation to JSON Schema keywords.
+func fieldJSONSchema(f *ConfigField) map[string]any {
+    s := map[string]any{}
+    switch f.Type {
+    case "string":
+        s["type"] = "string"
+    case "bool":
+        s["type"] = "boolean"
+    case "int":
+        s["type"] = "integer"
+    case "double":
+        s["type"] = "number"
+    case "duration":
+        s["type"] = []string{"string", "integer"}
+        s["pattern"] = durationPattern
+    case "enum":
+        s["type"] = "string"
+        if len(f.EnumValues) > 0 { s["enum"] = f.EnumValues }
+    case "stringArray":
+        s["type"] = "array"
+        s["items"] = map[string]any{"type": "string"}
+    case "array":
+        s["type"] = "array"
+        switch f.ItemType {
+        case "componentRef":
+            s["type"] = []string{"array", "string"}
+            s["items"] = map[string]any{"type": "string"}
+        case "string":
+            s["items"] = map[string]any{"type": "string"}
+        }
+    case "map", "stringMap":
+        s["type"] = "object"
+    }
+    switch f.Format {
+    case "url":
+        s["format"] = "uri"
+    case "hostport":
+        s["pattern"] = hostPortPattern
+    case "pem":
+        s["contentMediaType"] = "application/x-pem-file"
+    }
+    if f.Description != "" { s["description"] = f.Description }
+    if f.Deprecated { s["deprecated"] = true }
+    if f.Default != nil { s["default"] = f.Default }
+    if f.Type == "int" || f.Type == "double" {
+        for key, kw := range map[string]string{"min": "minimum", "minExclusive": "exclusiveMinimum", "max": "maximum", "maxExclusive": "exclusiveMaximum"} {
+            if v, ok := f.Validation[key]; ok {
+                if n, err := strconv.ParseFloat(v, 64); err == nil { s[kw] = n }
+            }
+        }
+    }
+    // Non-string and pattern-checked values may be given as references instead. Only type,
+    // pattern and enum constrain strings; items, minimum, ... do not apply to a reference.
+    if t, ok := s["type"]; ok && (t != "string" || s["pattern"] != nil) {
+        typed := map[string]any{"type": t}
+        if p, ok := s["pattern"]; ok { typed["pattern"] = p }
+        delete(s, "type")
+        delete(s, "pattern")
+        s["anyOf"] = []any{typed, map[string]any{"type": "string", "pattern": confmapRefPattern}}
+    } else if e, ok := s["enum"]; ok {
+        delete(s, "type")
+        delete(s, "enum")
+        s["anyOf"] = enumJSONSchema(e.([]string))
+    }
+    return s
+}
+
+// enumJSONSchema accepts an enum value in any case, as validate does, or a reference. The
+// plain enum alternative is kept for editor completion.
+func enumJSONSchema(values []string) []any {
+    alts := make([]string, 0, len(values))
+    for _, v := range values {
+        var b strings.Builder
+        for _, r := range v {
+            lo, up := strings.ToLower(string(r)), strings.ToUpper(string(r))
+            if lo != up {
+                b.WriteString("[" + lo + up + "]")
+            } else {
+                b.WriteString(regexp.QuoteMeta(string(r)))
+            }
+        }
+        alts = append(alts, b.String())
+    }
+    return []any{
+        map[string]any{"enum": values},
+        map[string]any{"type": "string", "pattern": "^(" + strings.Join(alts, "|") + ")$"},
+        map[string]any{"type": "string", "pattern": confmapRefPattern},
+    }
+}
+
+// requiredPathJSONSchema asserts that a (possibly nested) key is present.
+func requiredPathJSONSchema(tokens []string) map[string]any {
+    s := map[string]any{"required": []string{tokens[0]}}
+    if len(tokens) > 1 {
+        s["properties"] = map[string]any{tokens[0]: requiredPathJSONSchema(tokens[1:])}
+    }
+    return s
+}
+
+func constraintJSONSchema(cs Constraint) map[string]any {
+    var alts []any
+    for _, k := range cs.KeyTokens {
+        if len(k) > 0 { alts = append(alts, requiredPathJSONSchema(k)) }
+    }
+    if len(alts) < 2 { return nil }
+    var s map[string]any
+    switch cs.Kind {
+    case "anyOf":
+        s = map[string]any{"anyOf": alts}
+    case "oneOf":
+        s = map[string]any{"oneOf": alts}
+    case "allOf":
+        s = map[string]any{"allOf": alts}
+    case "atMostOne":
+        // No two keys may be set together.
+        var pairs []any
+        for i := 0; i < len(alts); i++ {
+            for j := i + 1; j < len(alts); j++ {
+                pairs = append(pairs, map[string]any{"allOf": []any{alts[i], alts[j]}})
+            }
+        }
+        s = map[string]any{"not": map[string]any{"anyOf": pairs}}
+    default:
+        return nil
+    }
+    if cs.Message != "" { s["description"] = cs.Message }
+    return s
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+    "encoding/json"
+    "path/filepath"
+    "regexp"
+    "testing"
+)
+
+// TestJSONSchemaGolden converts the fixture schema into the collector document schema,
+// compared with testdata/golden/jsonschema/collector.json.
+func TestJSONSchemaGolden(t *testing.T) {
+    data, err := loadExtractedData(schemaFixture)
+    if err != nil { t.Fatal(err) }
+    got, err := json.MarshalIndent(collectorJSONSchema(data), "", "  ")
+    if err != nil { t.Fatal(err) }
+    checkGolden(t, filepath.Join("testdata", "golden", "jsonschema", "collector.json"), append(got, '\n'))
+}
+
+// TestFieldJSONSchema checks that emitted field schemas accept confmap references in
+// place of typed or pattern-checked values, and enum values in any case.
+func TestFieldJSONSchema(t *testing.T) {
+    matches := func(s map[string]any, v string) bool {
+        for _, alt := range s["anyOf"].([]any) {
+            a := alt.(map[string]any)
+            if e, ok := a["enum"]; ok && containsToken(e.([]string), v) { return true }
+            if p, ok := a["pattern"].(string); ok && regexp.MustCompile(p).MatchString(v) { return true }
+        }
+        return false
+    }
+    port := fieldJSONSchema(&ConfigField{Type: "int"})
+    mode := fieldJSONSchema(&ConfigField{Type: "enum", EnumValues: []string{"fast", "safe"}})
+    timeout := fieldJSONSchema(&ConfigField{Type: "duration"})
+    endpoint := fieldJSONSchema(&ConfigField{Type: "string", Format: "hostport"})
+    for _, c := range []struct {
+        name   string
+        schema map[string]any
+        value  string
+        want   bool
+    }{
+        {"int reference", port, "${env:PORT}", true},
+        {"int with default", port, "${PORT:-4317}", true},
+        {"int literal string", port, "4317", false},
+        {"enum", mode, "fast", true},
+        {"enum other case", mode, "FAST", true},
+        {"enum reference", mode, "${env:MODE}", true},
+        {"enum unknown", mode, "turbo", false},
+        {"duration", timeout, "1m30s", true},
+        {"duration embedded reference", timeout, "${env:SECONDS}s", true},
+        {"duration invalid", timeout, "soon", false},
+        {"hostport", endpoint, "localhost:4317", true},
+        {"hostport any interface", endpoint, ":4317", true},
+        {"hostport ipv6", endpoint, "[::1]:4317", true},
+        {"hostport reference", endpoint, "${env:ENDPOINT}", true},
+        {"hostport without port", endpoint, "localhost", false},
+        {"hostport url", endpoint, "http://localhost:4317", false},
+    } {
+        if got := matches(c.schema, c.value); got != c.want { t.Errorf("%s: %q accepted = %v, want %v", c.name, c.value, got, c.want) }
+    }
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+    "flag"
+    "fmt"
+    "net"
+    "net/url"
+    "os"
+    "sort"
+    "strings"
+
+    "gopkg.in/yaml.v3"
+)
+
+// runLint implements: lint --schema=configs.json [--format=text|json] <config.yaml>...
+// It reports security problems in collector configs: literal values in sensitive fields,
+// inline private keys, and TLS disabled or unverified towards non-local endpoints.
+func runLint(args []string) int {
+    fs := flag.NewFlagSet("lint", flag.ExitOnError)
+    schemaPath := fs.String("schema", "", "Extracted configs JSON with sensitive/format field hints")
+    format := fs.String("format", "text", "Output format: text or json")
+    fs.Usage = func() {
+        fmt.Fprintln(os.Stderr, "Usage: go run . lint --schema=configs.json [--format=text|json] <collector.yaml>...")
+        fs.PrintDefaults()
+    }
+    _ = fs.Parse(args)
+    if *schemaPath == "" || fs.NArg() == 0 {
+        fs.Usage()
+        return 2
+    }
+    data, err := loadExtractedData(*schemaPath)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "lint: %v\n", err)
+        return 2
+    }
+    idx := newSchemaIndex(data)
+    var issues []ValidationIssue
+    for _, path := range fs.Args() {
+        content, err := os.ReadFile(path)
+        if err != nil {
+            fmt.Fprintf(os.Stderr, "lint: %v\n", err)
+            return 2
+        }
+        found, err := lintConfig(idx, path, content)
+        if err != nil {
+            fmt.Fprintf(os.Stderr, "lint: %s: %v\n", path, err)
+            return 2
+        }
+        issues = append(issues, found...)
+    }
+    if err := writeIssues(os.Stdout, *format, issues); err != nil {
+        fmt.Fprintf(os.Stderr, "lint: %v\n", err)
+        return 2
+    }
+    for _, is := range issues {
+        if is.Severity == "error" { return 1 }
+    }
+    return 0
+}
+
+// lintConfig runs the security checks over every component entry (and the service
+// section) of a collector config. Unknown components are left to validate.
+func lintConfig(idx *schemaIndex, file string, content []byte) ([]ValidationIssue, error) {
+    root, err := parseConfigYAML(content)
+    if err != nil || root == nil { return nil, err }
+    v := &configValidator{idx: idx, file: file}
+    for _, sec := range componentSections {
+        _, entries := mappingValue(root, sec.section)
+        if entries == nil || entries.Kind != yaml.MappingNode { continue }
+        for i := 0; i+1 < len(entries.Content); i += 2 {
+            idNode, cfgNode := entries.Content[i], entries.Content[i+1]
+            c := idx.component(sec.kind, idNode.Value)
+            if c == nil { continue }
+            v.component = sec.section + "::" + idNode.Value
+            v.lint(idx.tree(c), cfgNode, nil, nil)
+        }
+    }
+    if svc := idx.data.Service; svc != nil {
+        if _, cfgNode := mappingValue(root, "service"); cfgNode != nil {
+            v.component = "service"
+            v.lint(idx.tree(svc), cfgNode, nil, nil)
+        }
+    }
+    sort.SliceStable(v.issues, func(i, j int) bool {
+        if v.issues[i].Line != v.issues[j].Line { return v.issues[i].Line < v.issues[j].Line }
+        return v.issues[i].Column < v.issues[j].Column
+    })
+    return v.issues, nil
+}
+
+// lint walks a YAML value like walk, checking the leaves it resolves to fields.
+// parents are the enclosing mappings, innermost last, for the endpoint lookup.
+func (v *configValidator) lint(sn *schemaNode, n *yaml.Node, path []string, parents []*yaml.Node) {
+    if n == nil || isNullNode(n) { return }
+    if n.Kind == yaml.AliasNode && n.Alias != nil { n = n.Alias }
+    if sn.field != nil {
+        v.lintLeaf(sn.field, n, path, parents)
+        if items := sn.children["[]"]; items != nil && len(items.children) > 0 && n.Kind == yaml.SequenceNode {
+            for _, it := range n.Content { v.lint(items, it, append(append([]string{}, path...), "[]"), parents) }
+        }
+        if values := sn.children["{key}"]; values != nil && n.Kind == yaml.MappingNode {
+            for i := 0; i+1 < len(n.Content); i += 2 {
+                v.lint(values, n.Content[i+1], append(append([]string{}, path...), n.Content[i].Value), append(parents, n))
+            }
+        }
+        return
+    }
+    switch n.Kind {
+    case yaml.MappingNode:
+        for i := 0; i+1 < len(n.Content); i += 2 {
+            k := n.Content[i]
+            child := sn.children[k.Value]
+            if child == nil { child = sn.children["{key}"] }
+            if child == nil { continue }
+            v.lint(child, n.Content[i+1], append(append([]string{}, path...), k.Value), append(parents, n))
+        }
+    case yaml.SequenceNode:
+        if items := sn.children["[]"]; items != nil {
+            for _, it := range n.Content { v.lint(items, it, append(append([]string{}, path...), "[]"), parents) }
+        }
+    }
+}
+
+func (v *configValidator) lintLeaf(f *ConfigField, n *yaml.Node, path []string, parents []*yaml.Node) {
+    key := strings.Join(path, ".")
+    last := ""
+    if len(path) > 0 { last = path[len(path)-1] }
+    switch {
+    case f.Type == "bool" && (last == "insecure" || last == "insecure_skip_verify"):
+        v.checkInsecure(n, key, last, parents)
+    case n.Kind == yaml.ScalarNode:
+        v.checkSecret(f, n, key)
+    case n.Kind == yaml.MappingNode && f.Sensitive:
+        // Opaque maps (headers, ...) hold credentials under well-known keys
+        for i := 0; i+1 < len(n.Content); i += 2 {
+            k, val := n.Content[i], n.Content[i+1]
+            if val.Kind != yaml.ScalarNode || !secretKeyword(k.Value) || val.Value == "" || hasConfmapReference(val.Value) { continue }
+            v.report(val, "error", joinKey(key, k.Value), "literal secret; use an ${env:...} or ${file:...} reference")
+        }
+    }
+}
+
+// checkSecret flags private keys written inline and literal values of sensitive string
+// fields. PEM fields and *_file settings usually hold file paths, which are fine.
+func (v *configValidator) checkSecret(f *ConfigField, n *yaml.Node, key string) {
+    if n.Value == "" || hasConfmapReference(n.Value) { return }
+    if (f.Format == "pem" || f.Sensitive) && isPEMPrivateKey(n.Value) {
+        v.report(n, "error", key, "inline private key; reference a key file or use ${file:...}")
+        return
+    }
+    if !f.Sensitive || f.Format == "pem" || f.Type != "string" { return }
+    if strings.HasSuffix(key, "_file") || strings.HasSuffix(key, "_path") { return }
+    v.report(n, "error", key, "literal secret; use an ${env:...} or ${file:...} reference")
+}
+
+// checkInsecure flags insecure / insecure_skip_verify set to true when the nearest
+// endpoint of an enclosing mapping is not a loopback address.
+func (v *configValidator) checkInsecure(n *yaml.Node, key, setting string, parents []*yaml.Node) {
+    if n.Kind != yaml.ScalarNode || n.ShortTag() != "!!bool" || !strings.EqualFold(n.Value, "true") { return }
+    var endpoint string
+    for i := len(parents) - 1; i >= 0 && endpoint == ""; i-- {
+        if _, val := mappingValue(parents[i], "endpoint"); val != nil && val.Kind == yaml.ScalarNode { endpoint = val.Value }
+    }
+    // Without a literal endpoint the target is unknown
+    if endpoint == "" || hasConfmapReference(endpoint) { return }
+    host := endpointHost(endpoint)
+    if host == "" || isLoopbackHost(host) { return }
+    if setting == "insecure" {
+        v.report(n, "warning", key, fmt.Sprintf("TLS disabled for non-local endpoint %q", endpoint))
+    } else {
+        v.report(n, "warning", key, fmt.Sprintf("certificate verification disabled for non-local endpoint %q", endpoint))
+    }
+}
+
+func isPEMPrivateKey(s string) bool {
+    return strings.Contains(s, "-----BEGIN") && strings.Contains(s, "PRIVATE KEY-----")
+}
+
+// secretKeyword matches map keys (HTTP headers, ...) that carry credentials.
+func secretKeyword(k string) bool {
+    k = strings.ToLower(k)
+    for _, w := range []string{"authorization", "token", "password", "secret", "api-key", "api_key", "apikey"} {
+        if strings.Contains(k, w) { return true }
+    }
+    return false
+}
+
+// endpointHost extracts the host from a URL or host:port endpoint.
+func endpointHost(endpoint string) string {
+    if strings.Contains(endpoint, "://") {
+        u, err := url.Parse(endpoint)
+        if err != nil { return "" }
+        if u.Scheme == "unix" { return "" }
+        return u.Hostname()
+    }
+    if host, _, err := net.SplitHostPort(endpoint); err == nil { return host }
+    return strings.Trim(endpoint, "[]")
+}
+
+func isLoopbackHost(host string) bool {
+    if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") { return true }
+    ip := net.ParseIP(host)
+    return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,681 @@
+package main
+
+import (
+    "bufio"
+    "encoding/json"
+    "flag"
+    "fmt"
+    "io"
+    "os"
+    "regexp"
+    "strconv"
+    "strings"
+
+    "gopkg.in/yaml.v3"
+)
+
+// runLSP implements: lsp --schema=configs.json
+// It speaks the Language Server Protocol over stdin/stdout for collector YAML files.
+func runLSP(args []string) int {
+    fs := flag.NewFlagSet("lsp", flag.ExitOnError)
+    schemaPath := fs.String("schema", "", "Extracted configs JSON for the collector version being edited")
+    fs.Usage = func() {
+        fmt.Fprintln(os.Stderr, "Usage: go run . lsp --schema=configs.json   (LSP over stdio)")
+        fs.PrintDefaults()
+    }
+    _ = fs.Parse(args)
+    if *schemaPath == "" {
+        fs.Usage()
+        return 2
+    }
+    data, err := loadExtractedData(*schemaPath)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "lsp: %v\n", err)
+        return 1
+    }
+    s := &lspServer{idx: newSchemaIndex(data), docs: map[string]string{}, out: bufio.NewWriter(os.Stdout)}
+    if err := s.serve(os.Stdin); err != nil {
+        fmt.Fprintf(os.Stderr, "lsp: %v\n", err)
+        return 1
+    }
+    // The protocol asks for exit code 1 when exit arrives without a prior shutdown
+    if !s.shutdown { return 1 }
+    return 0
+}
+
+// lspServer handles one client. Requests are processed in order on a single goroutine.
+// Positions are treated as byte offsets, which matches UTF-16 for ASCII configs.
+type lspServer struct {
+    idx      *schemaIndex
+    docs     map[string]string // open documents by URI (full text sync)
+    out      *bufio.Writer
+    shutdown bool
+}
+
+type rpcRequest struct {
+    ID     json.RawMessage `json:"id,omitempty"`
+    Method string          `json:"method"`
+    Params json.RawMessage `json:"params,omitempty"`
+}
+
+// rpcResponse carries either a result or an error; JSON-RPC forbids both, so Result is
+// pre-encoded ("null" for an empty success) and omitted on error replies.
+type rpcResponse struct {
+    JSONRPC string          `json:"jsonrpc"`
+    ID      json.RawMessage `json:"id"`
+    Result  json.RawMessage `json:"result,omitempty"`
+    Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+    Code    int    `json:"code"`
+    Message string `json:"message"`
+}
+
+type rpcNotification struct {
+    JSONRPC string `json:"jsonrpc"`
+    Method  string `json:"method"`
+    Params  any    `json:"params"`
+}
+
+type lspPosition struct {
+    Line      int `json:"line"`
+    Character int `json:"character"`
+}
+
+type lspRange struct {
+    Start lspPosition `json:"start"`
+    End   lspPosition `json:"end"`
+}
+
+type lspLocation struct {
+    URI   string   `json:"uri"`
+    Range lspRange `json:"range"`
+}
+
+type lspDiagnostic struct {
+    Range    lspRange `json:"range"`
+    Severity int      `json:"severity"` // 1 error, 2 warning
+    Source   string   `json:"source"`
+    Message  string   `json:"message"`
+}
+
+type markupContent struct {
+    Kind  string `json:"kind"`
+    Value string `json:"value"`
+}
+
+type completionItem struct {
+    Label         string         `json:"label"`
+    Kind          int            `json:"kind"`
+    Detail        string         `json:"detail,omitempty"`
+    Documentation *markupContent `json:"documentation,omitempty"`
+    InsertText    string         `json:"insertText,omitempty"`
+}
+
+// LSP CompletionItemKind values used here.
+const (
+    completionKindModule   = 9
+    completionKindProperty = 10
+    completionKindValue    = 12
+    completionKindEnum     = 20
+)
+
+type textDocumentPositionParams struct {
+    TextDocument struct {
+        URI string `json:"uri"`
+    } `json:"textDocument"`
+    Position lspPosition `json:"position"`
+}
+
+// serve reads framed JSON-RPC messages until exit or EOF.
+func (s *lspServer) serve(in io.Reader) error {
+    r := bufio.NewReader(in)
+    for {
+        body, err := readRPCMessage(r)
+        if err == io.EOF { return nil }
+        if err != nil { return err }
+        var req rpcRequest
+        if err := json.Unmarshal(body, &req); err != nil {
+            s.send(rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: -32700, Message: err.Error()}})
+            continue
+        }
+        if req.Method == "exit" { return nil }
+        result, rerr := s.handle(req)
+        // Notifications carry no id and get no response
+        if len(req.ID) == 0 { continue }
+        resp := rpcResponse{JSONRPC: "2.0", ID: req.ID, Error: rerr}
+        if rerr == nil {
+            data, err := json.Marshal(result)
+            if err != nil {
+                resp.Error = &rpcError{Code: -32603, Message: err.Error()}
+            } else {
+                resp.Result = data
+            }
+        }
+        s.send(resp)
+    }
+}
+
+// readRPCMessage reads one Content-Length framed message body.
+func readRPCMessage(r *bufio.Reader) ([]byte, error) {
+    length := -1
+    for {
+        line, err := r.ReadString('\n')
+        if err != nil {
+            if err == io.EOF && line == "" && length < 0 { return nil, io.EOF }
+            return nil, err
+        }
+        line = strings.TrimRight(line, "\r\n")
+        if line == "" { break }
+        name, value, ok := strings.Cut(line, ":")
+        if !ok || !strings.EqualFold(strings.TrimSpace(name), "Content-Length") { continue }
+        n, err := strconv.Atoi(strings.TrimSpace(value))
+        if err != nil { return nil, fmt.Errorf("bad Content-Length %q", value) }
+        length = n
+    }
+    if length < 0 { return nil, fmt.Errorf("message without Content-Length") }
+    body := make([]byte, length)
+    _, err := io.ReadFull(r, body)
+    return body, err
+}
+
+func (s *lspServer) send(msg any) {
+    data, err := json.Marshal(msg)
+    if err != nil { return }
+    fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n", len(data))
+    _, _ = s.out.Write(data)
+    _ = s.out.Flush()
+}
+
+func (s *lspServer) handle(req rpcRequest) (any, *rpcError) {
+    switch req.Method {
+    case "initialize":
+        return map[string]any{
+            "capabilities": map[string]any{
+                "textDocumentSync":   1, // full document on every change
+                "completionProvider": map[string]any{"triggerCharacters": []string{":", " ", "-", "["}},
+                "hoverProvider":      true,
+                "definitionProvider": true,
+            },
+            "serverInfo": map[string]any{"name": "parse-otelcol", "version": s.idx.data.Version},
+        }, nil
+    case "shutdown":
+        s.shutdown = true
+        return nil, nil
+    case "textDocument/didOpen":
+        var p struct {
+            TextDocument struct {
+                URI  string `json:"uri"`
+                Text string `json:"text"`
+            } `json:"textDocument"`
+        }
+        if err := json.Unmarshal(req.Params, &p); err != nil { return nil, invalidParams(err) }
+        s.docs[p.TextDocument.URI] = p.TextDocument.Text
+        s.publishDiagnostics(p.TextDocument.URI)
+        return nil, nil
+    case "textDocument/didChange":
+        var p struct {
+            TextDocument struct {
+                URI string `json:"uri"`
+            } `json:"textDocument"`
+            ContentChanges []struct {
+                Text string `json:"text"`
+            } `json:"contentChanges"`
+        }
+        if err := json.Unmarshal(req.Params, &p); err != nil { return nil, invalidParams(err) }
+        if n := len(p.ContentChanges); n > 0 { s.docs[p.TextDocument.URI] = p.ContentChanges[n-1].Text }
+        s.publishDiagnostics(p.TextDocument.URI)
+        return nil, nil
+    case "textDocument/didClose":
+        var p textDocumentPositionParams
+        if err := json.Unmarshal(req.Params, &p); err != nil { return nil, invalidParams(err) }
+        delete(s.docs, p.TextDocument.URI)
+        s.send(rpcNotification{JSONRPC: "2.0", Method: "textDocument/publishDiagnostics", Params: map[string]any{"uri": p.TextDocument.URI, "diagnostics": []lspDiagnostic{}}})
+        return nil, nil
+    case "textDocument/completion", "textDocument/hover", "textDocument/definition":
+        var p textDocumentPositionParams
+        if err := json.Unmarshal(req.Params, &p); err != nil { return nil, invalidParams(err) }
+        text, ok := s.docs[p.TextDocument.URI]
+        if !ok { return nil, nil }
+        switch req.Method {
+        case "textDocument/completion":
+            return s.completion(text, p.Position), nil
+        case "textDocument/hover":
+            if h := s.hover(text, p.Position); h != nil { return h, nil }
+        default:
+            if loc := s.definition(p.TextDocument.URI, text, p.Position); loc != nil { return loc, nil }
+        }
+        return nil, nil
+    }
+    if len(req.ID) == 0 { return nil, nil }
+    return nil, &rpcError{Code: -32601, Message: "method not found: " + req.Method}
+}
+
+func invalidParams(err error) *rpcError { return &rpcError{Code: -32602, Message: err.Error()} }
+
+// publishDiagnostics runs validate on the document and reports its issues.
+func (s *lspServer) publishDiagnostics(uri string) {
+    s.send(rpcNotification{JSONRPC: "2.0", Method: "textDocument/publishDiagnostics", Params: map[string]any{
+        "uri":         uri,
+        "diagnostics": s.diagnostics(uri, s.docs[uri]),
+    }})
+}
+
+var yamlErrorLineRe = regexp.MustCompile(`line (\d+)`)
+
+func (s *lspServer) diagnostics(uri, text string) []lspDiagnostic {
+    lines := splitLines(text)
+    out := []lspDiagnostic{}
+    issues, err := validateConfig(s.idx, uri, []byte(text), validateOptions{})
+    if err != nil {
+        line := 1
+        if m := yamlErrorLineRe.FindStringSubmatch(err.Error()); m != nil { line, _ = strconv.Atoi(m[1]) }
+        return append(out, lspDiagnostic{Range: tokenRange(lines, line, 1), Severity: 1, Source: "otelcol", Message: err.Error()})
+    }
+    for _, is := range issues {
+        severity := 1
+        if is.Severity == "warning" { severity = 2 }
+        msg := is.Message
+        if is.Key != "" { msg = is.Key + ": " + msg }
+        out = append(out, lspDiagnostic{Range: tokenRange(lines, is.Line, is.Column), Severity: severity, Source: "otelcol", Message: msg})
+    }
+    return out
+}
+
+// tokenRange spans the YAML token starting at a 1-based line and column.
+func tokenRange(lines []string, line, col int) lspRange {
+    l, c := max(line-1, 0), max(col-1, 0)
+    end := c
+    if l < len(lines) {
+        s := lines[l]
+        if c >= len(s) { c = len(s) }
+        end = c
+        for end < len(s) && !strings.ContainsRune(" \t,]}#", rune(s[end])) {
+            if s[end] == ':' && (end+1 == len(s) || s[end+1] == ' ') { break }
+            end++
+        }
+    }
+    return lspRange{Start: lspPosition{l, c}, End: lspPosition{l, end}}
+}
+
+func splitLines(text string) []string {
+    lines := strings.Split(text, "\n")
+    for i := range lines { lines[i] = strings.TrimSuffix(lines[i], "\r") }
+    return lines
+}
+
+// cursorContext locates the cursor in a possibly incomplete document from indentation
+// alone. path holds the enclosing mapping keys ("[]" for sequence items). valueKey is
+// set when the value of that key is being typed; listItem when a sequence item of path is.
+type cursorContext struct {
+    path     []string
+    valueKey string
+    listItem bool
+}
+
+func cursorAt(lines []string, pos lspPosition) cursorContext {
+    var cc cursorContext
+    cur := ""
+    if pos.Line < len(lines) { cur = lines[pos.Line] }
+    if pos.Character < len(cur) { cur = cur[:pos.Character] }
+    indent := leadingSpaces(cur)
+    rest := cur[indent:]
+    // Parents of a "- " item may sit at the item's own indentation (indentless sequences)
+    sameIndentParent := false
+    // Path token contributed by the cursor line itself
+    own := ""
+    if rest == "-" || strings.HasPrefix(rest, "- ") {
+        sameIndentParent = true
+        item := strings.TrimSpace(rest[1:])
+        if k, v, ok := strings.Cut(item, ":"); ok && (v == "" || strings.HasPrefix(v, " ")) {
+            cc.valueKey, own = strings.TrimSpace(k), "[]"
+        } else {
+            cc.listItem = true
+        }
+    } else if k, v, ok := strings.Cut(rest, ":"); ok && (v == "" || strings.HasPrefix(v, " ")) {
+        if strings.HasPrefix(strings.TrimSpace(v), "[") {
+            cc.listItem, own = true, strings.TrimSpace(k)
+        } else {
+            cc.valueKey = strings.TrimSpace(k)
+        }
+    }
+
+    want := indent
+    for i := pos.Line - 1; i >= 0 && (want > 0 || sameIndentParent); i-- {
+        l := lines[i]
+        t := strings.TrimSpace(l)
+        if t == "" || strings.HasPrefix(t, "#") { continue }
+        ind := leadingSpaces(l)
+        body := l[ind:]
+        if body == "-" || strings.HasPrefix(body, "- ") {
+            // Sibling items, or items of a sequence nested deeper than the cursor
+            if ind >= want { continue }
+            // A sequence item whose mapping content starts two columns in
+            inner := strings.TrimSpace(body[1:])
+            if k := yamlLineKey(inner); k != "" && want > ind+2 && yamlLineValue(inner) == "" { cc.path = append([]string{k}, cc.path...) }
+            cc.path = append([]string{"[]"}, cc.path...)
+            want, sameIndentParent = ind, true
+            continue
+        }
+        if ind < want || (sameIndentParent && ind == want) {
+            k := yamlLineKey(body)
+            if k == "" { continue }
+            cc.path = append([]string{k}, cc.path...)
+            want, sameIndentParent = ind, false
+        }
+    }
+    if own != "" { cc.path = append(cc.path, own) }
+    return cc
+}
+
+func leadingSpaces(s string) int { return len(s) - len(strings.TrimLeft(s, " ")) }
+
+// yamlLineKey returns the key of a "key: value" line, unquoted, or "".
+func yamlLineKey(s string) string {
+    k, v, ok := strings.Cut(s, ":")
+    if !ok || (v != "" && !strings.HasPrefix(v, " ")) { return "" }
+    return strings.Trim(strings.TrimSpace(k), `"'`)
+}
+
+func yamlLineValue(s string) string {
+    _, v, _ := strings.Cut(s, ":")
+    if i := strings.Index(v, " #"); i >= 0 { v = v[:i] }
+    return strings.TrimSpace(v)
+}
+
+// completion offers section names, component types, config keys, enum values and
+// pipeline references depending on where the cursor is.
+func (s *lspServer) completion(text string, pos lspPosition) []completionItem {
+    lines := splitLines(text)
+    cc := cursorAt(lines, pos)
+    items := []completionItem{}
+    switch {
+    case cc.listItem:
+        items = append(items, s.referenceCompletions(cc.path, lines)...)
+        // A "- " item of a list of objects starts with one of the element keys
+        items = append(items, s.keyCompletions(append(cc.path, "[]"))...)
+    case cc.valueKey != "":
+        items = append(items, s.valueCompletions(append(cc.path, cc.valueKey))...)
+    default:
+        items = append(items, s.keyCompletions(cc.path)...)
+    }
+    return items
+}
+
+// sectionKind returns the component type of a top-level config section.
+func sectionKind(section string) string {
+    for _, sec := range componentSections {
+        if sec.section == section { return sec.kind }
+    }
+    return ""
+}
+
+// componentField resolves a document path below a component entry (or below service:,
+// when its schema was extracted) to its schema node.
+func (s *lspServer) componentField(path []string) (*Component, *schemaNode) {
+    if len(path) > 0 && path[0] == "service" && s.idx.data.Service != nil {
+        return s.fieldBelow(s.idx.data.Service, path[1:])
+    }
+    if len(path) < 2 { return nil, nil }
+    kind := sectionKind(path[0])
+    if kind == "" { return nil, nil }
+    c := s.idx.component(kind, path[1])
+    if c == nil { return nil, nil }
+    return s.fieldBelow(c, path[2:])
+}
+
+// fieldBelow follows path from the root of a component's schema tree.
+func (s *lspServer) fieldBelow(c *Component, path []string) (*Component, *schemaNode) {
+    n := s.idx.tree(c)
+    for _, t := range path {
+        next := n.children[t]
+        if next == nil { next = n.children["{key}"] }
+        if next == nil { return c, nil }
+        n = next
+    }
+    return c, n
+}
+
+func (s *lspServer) keyCompletions(path []string) []completionItem {
+    var out []completionItem
+    property := func(label, detail string) {
+        out = append(out, completionItem{Label: label, Kind: completionKindProperty, Detail: detail, InsertText: label + ":"})
+    }
+    doc := s.idx.data.Document
+    switch {
+    case len(path) == 0:
+        for _, sec := range componentSections { property(sec.section, "") }
+        property("service", "")
+        return out
+    case len(path) == 1 && sectionKind(path[0]) != "":
+        kind := sectionKind(path[0])
+        for _, k := range sortedKeys(s.idx.components) {
+            if c := s.idx.components[k]; c.Type == kind {
+                out = append(out, completionItem{Label: c.Name, Kind: completionKindModule, Detail: kind, Documentation: markdown(componentMarkdown(c)), InsertText: c.Name + ":"})
+            }
+        }
+        return out
+    case path[0] == "service":
+        switch {
+        case len(path) == 2 && path[1] == "pipelines":
+            for _, sig := range doc.Signals { property(sig, "pipeline") }
+            return out
+        case len(path) == 3 && path[1] == "pipelines":
+            for _, k := range []string{"receivers", "processors", "exporters"} { property(k, "component IDs") }
+            return out
+        case s.idx.data.Service != nil:
+            // Everything else comes from the extracted service schema below
+        case len(path) == 1:
+            for _, k := range []string{"extensions", "pipelines", "telemetry"} { property(k, "") }
+        case len(path) == 2 && path[1] == "telemetry":
+            property("metrics", "")
+        case len(path) == 3 && path[1] == "telemetry" && path[2] == "metrics":
+            property("level", "")
+        }
+        if s.idx.data.Service == nil { return out }
+    }
+    _, n := s.componentField(path)
+    if n == nil { return out }
+    for _, k := range sortedKeys(n.children) {
+        if k == "[]" || k == "{key}" { continue }
+        child := n.children[k]
+        item := completionItem{Label: k, Kind: completionKindProperty, InsertText: k + ":"}
+        if f := child.field; f != nil {
+            item.Detail = f.Type
+            if f.Required { item.Detail += " (required)" }
+            item.Documentation = markdown(fieldMarkdown(f))
+            if len(child.children) == 0 { item.InsertText = k + ": " }
+        }
+        out = append(out, item)
+    }
+    return out
+}
+
+func (s *lspServer) valueCompletions(path []string) []completionItem {
+    var values []string
+    if strings.Join(path, ".") == "service.telemetry.metrics.level" {
+        values = s.idx.data.Document.Telemetry.MetricsLevels
+    } else if _, n := s.componentField(path); n != nil && n.field != nil {
+        switch n.field.Type {
+        case "enum":
+            values = n.field.EnumValues
+        case "bool":
+            values = []string{"true", "false"}
+        }
+    }
+    out := []completionItem{}
+    for _, v := range values { out = append(out, completionItem{Label: v, Kind: completionKindEnum}) }
+    return out
+}
+
+// referenceCompletions offers the IDs defined in the document for service.extensions and
+// the receivers/processors/exporters lists of a pipeline.
+func (s *lspServer) referenceCompletions(path []string, lines []string) []completionItem {
+    var sections []string
+    switch {
+    case len(path) == 2 && path[0] == "service" && path[1] == "extensions":
+        sections = []string{"extensions"}
+    case len(path) == 4 && path[0] == "service" && path[1] == "pipelines":
+        sections = pipelineRefs(s.idx.data.Document)[path[3]]
+    default:
+        return nil
+    }
+    defined := definedIDs(lines)
+    var out []completionItem
+    for _, sec := range sections {
+        for _, id := range defined[sec] {
+            item := completionItem{Label: id, Kind: completionKindValue, Detail: strings.TrimSuffix(sec, "s")}
+            if c := s.idx.component(sectionKind(sec), id); c != nil { item.Documentation = markdown(componentMarkdown(c)) }
+            out = append(out, item)
+        }
+    }
+    return out
+}
+
+// definedIDs lists the entry IDs under each top-level section. Lines are scanned so a
+// document that does not parse yet still completes.
+func definedIDs(lines []string) map[string][]string {
+    out := map[string][]string{}
+    section, childIndent := "", -1
+    for _, l := range lines {
+        t := strings.TrimSpace(l)
+        if t == "" || strings.HasPrefix(t, "#") { continue }
+        ind := leadingSpaces(l)
+        if ind == 0 {
+            section, childIndent = yamlLineKey(t), -1
+            continue
+        }
+        if section == "" { continue }
+        if childIndent < 0 { childIndent = ind }
+        if ind != childIndent { continue }
+        if k := yamlLineKey(t); k != "" { out[section] = append(out[section], k) }
+    }
+    return out
+}
+
+// hover documents component IDs (in their section or in pipelines) and config keys.
+func (s *lspServer) hover(text string, pos lspPosition) any {
+    root, err := parseConfigYAML([]byte(text))
+    if err != nil || root == nil { return nil }
+    n, path, isKey := yamlNodeAt(root, pos.Line+1, pos.Character+1, nil)
+    if n == nil { return nil }
+    var md string
+    switch {
+    case isKey && len(path) == 2:
+        if c := s.idx.component(sectionKind(path[0]), path[1]); c != nil { md = componentMarkdown(c) }
+    case len(path) >= 3 && sectionKind(path[0]) != "", len(path) >= 2 && path[0] == "service" && s.referencedSections(path) == nil:
+        // Keys and their scalar values both describe the field
+        if _, sn := s.componentField(path); sn != nil && sn.field != nil { md = fieldMarkdown(sn.field) }
+    case !isKey:
+        if c := s.referencedComponent(root, path, n.Value); c != nil { md = componentMarkdown(c) }
+    }
+    if md == "" { return nil }
+    return map[string]any{"contents": markdown(md), "range": nodeRange(n)}
+}
+
+// definition jumps from an ID in service.extensions or a pipeline list to its entry.
+func (s *lspServer) definition(uri, text string, pos lspPosition) any {
+    root, err := parseConfigYAML([]byte(text))
+    if err != nil || root == nil { return nil }
+    n, path, isKey := yamlNodeAt(root, pos.Line+1, pos.Character+1, nil)
+    if n == nil || isKey { return nil }
+    for _, sec := range s.referencedSections(path) {
+        _, entries := mappingValue(root, sec)
+        if k, _ := mappingValue(entries, n.Value); k != nil { return lspLocation{URI: uri, Range: nodeRange(k)} }
+    }
+    return nil
+}
+
+// referencedSections returns the config sections an ID at path may refer to.
+func (s *lspServer) referencedSections(path []string) []string {
+    switch {
+    case len(path) == 3 && path[0] == "service" && path[1] == "extensions" && path[2] == "[]":
+        return []string{"extensions"}
+    case len(path) == 5 && path[0] == "service" && path[1] == "pipelines" && path[4] == "[]":
+        return pipelineRefs(s.idx.data.Document)[path[3]]
+    }
+    return nil
+}
+
+// referencedComponent resolves a pipeline or extension reference to its component.
+func (s *lspServer) referencedComponent(root *yaml.Node, path []string, id string) *Component {
+    for _, sec := range s.referencedSections(path) {
+        _, entries := mappingValue(root, sec)
+        if k, _ := mappingValue(entries, id); k != nil { return s.idx.component(sectionKind(sec), id) }
+    }
+    return nil
+}
+
+// yamlNodeAt finds the scalar at a 1-based line and column, with its path ("[]" for
+// sequence items) and whether it is a mapping key.
+func yamlNodeAt(n *yaml.Node, line, col int, path []string) (*yaml.Node, []string, bool) {
+    switch n.Kind {
+    case yaml.MappingNode:
+        for i := 0; i+1 < len(n.Content); i += 2 {
+            k, v := n.Content[i], n.Content[i+1]
+            p := append(append([]string{}, path...), k.Value)
+            if scalarContains(k, line, col) { return k, p, true }
+            if found, fp, isKey := yamlNodeAt(v, line, col, p); found != nil { return found, fp, isKey }
+        }
+    case yaml.SequenceNode:
+        p := append(append([]string{}, path...), "[]")
+        for _, it := range n.Content {
+            if found, fp, isKey := yamlNodeAt(it, line, col, p); found != nil { return found, fp, isKey }
+        }
+    case yaml.ScalarNode:
+        if scalarContains(n, line, col) { return n, path, false }
+    }
+    return nil, nil, false
+}
+
+func scalarContains(n *yaml.Node, line, col int) bool {
+    if n.Kind != yaml.ScalarNode || n.Line != line { return false }
+    return col >= n.Column && col <= n.Column+scalarWidth(n)
+}
+
+func scalarWidth(n *yaml.Node) int {
+    if n.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0 { return len(n.Value) + 2 }
+    return len(n.Value)
+}
+
+func nodeRange(n *yaml.Node) lspRange {
+    start := lspPosition{n.Line - 1, n.Column - 1}
+    return lspRange{Start: start, End: lspPosition{start.Line, start.Character + scalarWidth(n)}}
+}
+
+func markdown(s string) *markupContent { return &markupContent{Kind: "markdown", Value: s} }
+
+// componentMarkdown is the hover text for a component type.
+func componentMarkdown(c *Component) string {
+    var b strings.Builder
+    fmt.Fprintf(&b, "**%s** %s", c.Name, c.Type)
+    if c.Description != "" { fmt.Fprintf(&b, "\n\n%s", c.Description) }
+    if len(c.Signals) > 0 { fmt.Fprintf(&b, "\n\nSignals: %s", strings.Join(c.Signals, ", ")) }
+    if len(c.Stability) > 0 {
+        var levels []string
+        for _, k := range sortedKeys(c.Stability) { levels = append(levels, k+": "+c.Stability[k]) }
+        fmt.Fprintf(&b, "\n\nStability: %s", strings.Join(levels, ", "))
+    }
+    return b.String()
+}
+
+// fieldMarkdown is the hover text for a config key.
+func fieldMarkdown(f *ConfigField) string {
+    var b strings.Builder
+    fmt.Fprintf(&b, "**`%s`** `%s`", fieldKey(f), f.Type)
+    if f.Description != "" { fmt.Fprintf(&b, "\n\n%s", f.Description) }
+    var facts []string
+    if f.Required { facts = append(facts, "Required") }
+    if f.Default != nil { facts = append(facts, "Default: `"+formatDiffValue(f.Default)+"`") }
+    if f.Unit != "" { facts = append(facts, "Unit: "+f.Unit) }
+    if f.Format != "" { facts = append(facts, "Format: "+f.Format) }
+    if len(f.EnumValues) > 0 { facts = append(facts, "One of: `"+strings.Join(f.EnumValues, "`, `")+"`") }
+    if f.Deprecated {
+        d := "Deprecated"
+        if f.Replacement != "" { d += "; use `" + f.Replacement + "` instead" }
+        facts = append(facts, d)
+    }
+    if len(facts) > 0 { fmt.Fprintf(&b, "\n\n- %s", strings.Join(facts, "\n- ")) }
+    return b.String()
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+    "bufio"
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "io"
+    "strings"
+    "testing"
+)
+
+// TestCursorAt locates the cursor ("|") in incomplete documents from indentation alone.
+func TestCursorAt(t *testing.T) {
+    tests := []struct {
+        name, doc string
+        want      cursorContext
+    }{
+        {"top level", "rec|", cursorContext{}},
+        {"nested key", "receivers:\n  otlp:\n    protocols:\n      |", cursorContext{path: []string{"receivers", "otlp", "protocols"}}},
+        {"value", "processors:\n  batch:\n    timeout: |", cursorContext{path: []string{"processors", "batch"}, valueKey: "timeout"}},
+        {"skips comments and blanks", "processors:\n  batch:\n\n    # timeout: 1s\n    |", cursorContext{path: []string{"processors", "batch"}}},
+        {"indentless sequence", "service:\n  extensions:\n  - health\n  - |", cursorContext{path: []string{"service", "extensions"}, listItem: true}},
+        {"indented sequence", "service:\n  pipelines:\n    traces:\n      receivers:\n        - otlp\n        - |", cursorContext{path: []string{"service", "pipelines", "traces", "receivers"}, listItem: true}},
+        {"key item value", "processors:\n  routing:\n    table:\n      - statement: |", cursorContext{path: []string{"processors", "routing", "table", "[]"}, valueKey: "statement"}},
+        {"key item sibling", "processors:\n  routing:\n    table:\n      - statement: x\n        exporters: |", cursorContext{path: []string{"processors", "routing", "table", "[]"}, valueKey: "exporters"}},
+        {"key item nested", "processors:\n  routing:\n    tenants:\n      a:\n        headers:\n          - key: a\n            |", cursorContext{path: []string{"processors", "routing", "tenants", "a", "headers", "[]"}}},
+        {"indentless key item", "processors:\n  routing:\n    table:\n    - statement: x\n      |", cursorContext{path: []string{"processors", "routing", "table", "[]"}}},
+        {"flow sequence", "service:\n  pipelines:\n    traces:\n      exporters: [|", cursorContext{path: []string{"service", "pipelines", "traces", "exporters"}, listItem: true}},
+        {"flow sequence item", "service:\n  pipelines:\n    traces:\n      exporters: [otlp, |]", cursorContext{path: []string{"service", "pipelines", "traces", "exporters"}, listItem: true}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            lines := splitLines(strings.Replace(tt.doc, "|", "", 1))
+            before := splitLines(tt.doc[:strings.Index(tt.doc, "|")])
+            got := cursorAt(lines, lspPosition{Line: len(before) - 1, Character: len(before[len(before)-1])})
+            if strings.Join(got.path, ".") != strings.Join(tt.want.path, ".") || got.valueKey != tt.want.valueKey || got.listItem != tt.want.listItem {
+                t.Errorf("cursorAt = %+v, want %+v", got, tt.want)
+            }
+        })
+    }
+}
+
+// TestDefinedIDs lists entry IDs per section, also from documents that do not parse.
+func TestDefinedIDs(t *testing.T) {
+    doc := `receivers:
+  # zipkin:
+  otlp:
+    protocols:
+      grpc:
+  "otlp/2":
+
+processors:
+    routing:
+      table: [
+    batch:
+exporters: {}
+service:
+  pipelines:
+`
+    got, err := json.Marshal(definedIDs(splitLines(doc)))
+    if err != nil { t.Fatal(err) }
+    want := `{"processors":["routing","batch"],"receivers":["otlp","otlp/2"],"service":["pipelines"]}`
+    if string(got) != want { t.Errorf("definedIDs = %s, want %s", got, want) }
+}
+
+// TestReadRPCMessage checks Content-Length framing: extra headers, header case, back to
+// back messages, a clean EOF between messages and malformed input.
+func TestReadRPCMessage(t *testing.T) {
+    r := bufio.NewReader(strings.NewReader("Content-Length: 7\r\nContent-Type: application/vscode-jsonrpc; charset=utf-8\r\n\r\n{\"a\":1}content-length:2\r\n\r\n{}"))
+    for _, want := range []string{`{"a":1}`, `{}`} {
+        body, err := readRPCMessage(r)
+        if err != nil || string(body) != want { t.Fatalf("readRPCMessage = %q, %v; want %q", body, err, want) }
+    }
+    if _, err := readRPCMessage(r); err != io.EOF { t.Errorf("at end: err = %v, want EOF", err) }
+    for input, want := range map[string]string{
+        "Content-Type: x\r\n\r\n{}":    "message without Content-Length",
+        "Content-Length: ten\r\n\r\n{}": `bad Content-Length " ten"`,
+        "Content-Length: 10\r\n\r\n{}":  "unexpected EOF",
+        "Content-Length: 2\r\n":         "EOF",
+    } {
+        _, err := readRPCMessage(bufio.NewReader(strings.NewReader(input)))
+        if err == nil || err.Error() != want { t.Errorf("%q: err = %v, want %s", input, err, want) }
+    }
+}
+
+// TestLSPSession drives the server over framed stdio with a fixture config: hover on a
+// component ID, a config key and a pipeline reference, and a jump to a referenced entry.
+func TestLSPSession(t *testing.T) {
+    config := `receivers:
+  otlp:
+exporters:
+  otlp:
+    compression: zstd
+    endpoint: backend:4317
+    token: secret
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [otlp]
+`
+    var in bytes.Buffer
+    id := 0
+    send := func(method string, params any) {
+        msg := map[string]any{"jsonrpc": "2.0", "method": method, "params": params}
+        if method != "textDocument/didOpen" && method != "exit" {
+            id++
+            msg["id"] = id
+        }
+        data, err := json.Marshal(msg)
+        if err != nil { t.Fatal(err) }
+        fmt.Fprintf(&in, "Content-Length: %d\r\n\r\n%s", len(data), data)
+    }
+    at := func(line, char int) map[string]any {
+        return map[string]any{"textDocument": map[string]any{"uri": "file:///collector.yaml"}, "position": map[string]any{"line": line, "character": char}}
+    }
+    send("initialize", map[string]any{})
+    send("textDocument/didOpen", map[string]any{"textDocument": map[string]any{"uri": "file:///collector.yaml", "text": config}})
+    send("textDocument/hover", at(3, 3))
+    send("textDocument/hover", at(4, 6))
+    send("textDocument/hover", at(11, 19))
+    send("textDocument/definition", at(11, 19))
+    send("textDocument/definition", at(4, 19))
+    send("textDocument/formatting", at(0, 0))
+    send("shutdown", nil)
+    send("exit", nil)
+
+    var out bytes.Buffer
+    s := &lspServer{idx: fixtureIndex(t), docs: map[string]string{}, out: bufio.NewWriter(&out)}
+    if err := s.serve(&in); err != nil { t.Fatal(err) }
+    if !s.shutdown { t.Error("shutdown not recorded") }
+
+    results := map[int]json.RawMessage{}
+    var failed []string
+    r := bufio.NewReader(&out)
+    for {
+        body, err := readRPCMessage(r)
+        if err == io.EOF { break }
+        if err != nil { t.Fatal(err) }
+        var msg struct {
+            ID     int             `json:"id"`
+            Method string          `json:"method"`
+            Params json.RawMessage `json:"params"`
+            Result json.RawMessage `json:"result"`
+            Error  *rpcError       `json:"error"`
+        }
+        if err := json.Unmarshal(body, &msg); err != nil { t.Fatal(err) }
+        if msg.Error != nil {
+            // JSON-RPC error replies must not carry a result member, not even null
+            var raw map[string]json.RawMessage
+            if err := json.Unmarshal(body, &raw); err != nil { t.Fatal(err) }
+            if _, ok := raw["result"]; ok { t.Errorf("error reply %d has a result: %s", msg.ID, body) }
+            failed = append(failed, fmt.Sprintf("%d %d", msg.ID, msg.Error.Code))
+            continue
+        }
+        if msg.Method == "textDocument/publishDiagnostics" {
+            if !strings.Contains(string(msg.Params), `"diagnostics":[]`) { t.Errorf("diagnostics: %s", msg.Params) }
+            continue
+        }
+        results[msg.ID] = msg.Result
+    }
+    hover := func(id int) string {
+        var h struct{ Contents markupContent }
+        if err := json.Unmarshal(results[id], &h); err != nil { t.Fatalf("hover %d: %s", id, results[id]) }
+        return h.Contents.Value
+    }
+    if h := hover(2); !strings.HasPrefix(h, "**otlp** exporter") { t.Errorf("component hover = %q", h) }
+    if h := hover(3); !strings.HasPrefix(h, "**`compression`** `enum`") || !strings.Contains(h, "One of: `gzip`, `zstd`, `none`") { t.Errorf("key hover = %q", h) }
+    if h := hover(4); !strings.HasPrefix(h, "**otlp** exporter") { t.Errorf("reference hover = %q", h) }
+    if got, want := string(results[5]), `{"uri":"file:///collector.yaml","range":{"start":{"line":3,"character":2},"end":{"line":3,"character":6}}}`; got != want { t.Errorf("definition = %s, want %s", got, want) }
+    if got := string(results[6]); got != "null" { t.Errorf("definition of a plain value = %s, want null", got) }
+    if got := strings.Join(failed, ","); got != "7 -32601" { t.Errorf("error replies = %q, want the unknown method only", got) }
+    if got := string(results[8]); got != "null" { t.Errorf("shutdown = %s, want null", got) }
+}
@@ -8,12 +8,14 @@ import (
     "go/parser"
     "go/token"
     "go/printer"
+    "go/types"
     "bytes"
     packages "golang.org/x/tools/go/packages"
     "io/ioutil"
     "os"
     "path/filepath"
     "reflect"
+    "regexp"
     "runtime"
     "sort"
     "strings"
@@ -35,17 +37,45 @@ type ExtractedData struct {
     Version    string      `json:"version"`
     Components []Component `json:"components"`
     Document   DocumentSchema `json:"document"`
-    // Optional: shared type definitions (reserved for future reuse)
-    Definitions map[string]any `json:"definitions,omitempty"`
+    // The service:: section (telemetry, extensions, pipelines) as a pseudo-component of type "service"
+    Service    *Component     `json:"service,omitempty"`
+    // Distributions read from the releases repo manifests (empty when --releases-path is unset)
+    Releases   []string       `json:"releases,omitempty"`
+    // Feature gates registered in either repo, linked to the components using them
+    FeatureGates []FeatureGate `json:"feature_gates,omitempty"`
+    // Shared config structs (confighttp.ClientConfig, configretry.BackOffConfig, ...) keyed
+    // "<import path>.<Type>"; component fields reference them with "$ref"
+    Definitions map[string]Definition `json:"definitions,omitempty"`
 }
 
 type Component struct {
     Name        string       `json:"name"`
     Type        string       `json:"type"` // receiver, processor, exporter
-    Module      string       `json:"-"`
+    Module      string       `json:"module,omitempty"` // Go import path, e.g. for ocb gomod lines
     Description string       `json:"description"`
     Config      ConfigSchema `json:"config"`
     Constraints []Constraint `json:"constraints"`
+    // Signals declared by the factory (receiver.WithTraces, processor.WithLogs, ...)
+    Signals     []string     `json:"signals,omitempty"`
+    // Connector signal pairs declared by the factory (connector.WithTracesToMetrics, ...)
+    SignalPairs []SignalPair `json:"signal_pairs,omitempty"`
+    // Stability level per signal ("traces", "traces_to_metrics", "extension") as in metadata.yaml status.stability
+    Stability map[string]string `json:"stability,omitempty"`
+    // From metadata.yaml: status.distributions / status.codeowners.active, emitted metrics and resource attributes
+    Distributions      []string            `json:"distributions,omitempty"`
+    // Official distributions whose releases manifest ships the component (otelcol, otelcol-contrib, ...)
+    Releases           []string            `json:"releases,omitempty"`
+    Codeowners         []string            `json:"codeowners,omitempty"`
+    Metrics            []EmittedMetric     `json:"metrics,omitempty"`
+    ResourceAttributes []ResourceAttribute `json:"resource_attributes,omitempty"`
+    // Some config struct decodes itself (confmap.Unmarshaler, UnmarshalText), so the schema is lossy
+    CustomUnmarshal    bool                `json:"custom_unmarshal,omitempty"`
+}
+
+// SignalPair is a from/to combination supported by a connector.
+type SignalPair struct {
+    From string `json:"from"`
+    To   string `json:"to"`
 }
 
 type ConfigSchema struct {
@@ -75,6 +105,26 @@ type ConfigField struct {
     ItemType     string            `json:"item_type,omitempty"` // e.g., "string", "object", "componentRef"
     RefKind      string            `json:"ref_kind,omitempty"`  // e.g., "extension", "receiver", ...
     RefScope     string            `json:"ref_scope,omitempty"` // e.g., "authenticator", "middleware"
+    // Declared by (or below) a struct with a custom Unmarshal/UnmarshalText: the YAML shape may differ
+    CustomUnmarshal bool           `json:"custom_unmarshal,omitempty"`
+    // Marked deprecated by its doc comment or by Validate()/Unmarshal(); Replacement is the YAML key to use instead
+    Deprecated   bool              `json:"deprecated,omitempty"`
+    Replacement  string            `json:"replacement,omitempty"`
+    // Go struct declaring the field ("<import path>.<Type>") and the path tokens it sits at
+    DeclaredIn   string            `json:"declared_in,omitempty"`
+    DeclaredPath []string          `json:"declared_path,omitempty"`
+    // Outermost shared config struct (declared outside the component) the field was extracted
+    // through, as a Definitions key, and the path tokens it is embedded at
+    Shared       string            `json:"shared,omitempty"`
+    SharedPath   []string          `json:"shared_path,omitempty"`
+    // A "$ref" field stands for every field of a definition, embedded at PathTokens. Overrides
+    // are this component's differing fields (e.g. its defaults) and Omit the keys it lacks,
+    // both relative to the embedding path
+    Ref          string            `json:"$ref,omitempty"`
+    Overrides    []ConfigField     `json:"overrides,omitempty"`
+    Omit         []string          `json:"omit,omitempty"`
+    replacementQuoted bool // Replacement was written as `key`; kept even when no field matches
+    origins      []fieldOrigin // named structs the field was extracted through, innermost first
 }
 
 type DefaultValue struct {
@@ -102,6 +152,8 @@ type DocumentSchema struct {
         Exporters  bool `json:"exporters"`
         Connectors bool `json:"connectors"`
     } `json:"pipeline_shape"`
+    // Config sections each pipeline list may reference (connectors act as both exporter and receiver).
+    PipelineRefs map[string][]string `json:"pipeline_refs"`
     // Telemetry levels (service.telemetry)
     Telemetry struct {
         MetricsLevels []string `json:"metrics_levels"` // none, basic, normal, detailed
@@ -112,12 +164,15 @@ type DocumentSchema struct {
 // Package parsing helpers for recursive extraction
 type packageContext struct {
     dir         string
+    path        string // import path
     files       []*ast.File
     fset        *token.FileSet
     imports     map[string]string // alias -> import path
     types       map[string]*ast.StructType
     aliases     map[string]ast.Expr // named type -> underlying expr
     importCache map[string]*packageContext // resolved external packages
+    tpkg        *types.Package // --typed only
+    tinfo       *types.Info    // --typed only
 }
 
 // Global package cache to avoid re-loading packages repeatedly across components
@@ -139,24 +194,44 @@ var (
     singleName   = flag.String("single-name", "", "Extract only component with this canonical name (e.g., otlp)")
     singleType   = flag.String("single-type", "", "Component type when using --single-name (receiver|processor|exporter|extension|connector)")
     printSchema  = flag.Bool("print", false, "Print extracted YAML keys for --single-name instead of writing JSON")
+    typed        = flag.Bool("typed", false, "Type-check packages (go/types) to classify fields, enums and struct types instead of guessing from the AST")
+    cacheDir     = flag.String("cache-dir", "", "Directory for the per-component extraction cache (reused across versions; empty disables)")
+    releasesPath = flag.String("releases-path", "", "Path to opentelemetry-collector-releases repo (distributions/*/manifest.yaml); optional")
 )
 
+// Subcommands that operate on previously extracted JSON. Without a subcommand
+// the program runs the extractor using the global flags below.
+var subcommands = map[string]func(args []string) int{
+    "builder":    runBuilder,
+    "diff":       runDiff,
+    "embedders":  runEmbedders,
+    "generate":   runGenerate,
+    "jsonschema": runJSONSchema,
+    "lint":       runLint,
+    "lsp":        runLSP,
+    "validate":   runValidate,
+}
+
 func main() {
+    if len(os.Args) > 1 {
+        if cmd, ok := subcommands[os.Args[1]]; ok {
+            os.Exit(cmd(os.Args[2:]))
+        }
+    }
     flag.Parse()
 
     if *version == "" || *collectorPath == "" || *contribPath == "" {
-        fmt.Println("Usage: go run extract_configs.go --version=v0.91.0 --collector-path=../opentelemetry-collector --contrib-path=../opentelemetry-collector-contrib --output=configs.json")
+        fmt.Println("Usage: go run . --version=v0.91.0 --collector-path=../opentelemetry-collector --contrib-path=../opentelemetry-collector-contrib --output=configs.json")
         os.Exit(1)
     }
 
     fmt.Printf("Extracting configs for version %s\n", *version)
 
-    // Pre-warm global package cache for both repos to speed up lookups
-    prewarmPackageCache(*collectorPath)
-    prewarmPackageCache(*contribPath)
-
     // Single-component mode for debugging/iteration
     if *singleName != "" && *singleType != "" {
+        // Pre-warm global package cache for both repos to speed up lookups
+        prewarmPackageCache(*collectorPath)
+        prewarmPackageCache(*contribPath)
         dir := findComponentDirByID(*collectorPath, *singleType, *singleName)
         isContrib := false
         if dir == "" {
@@ -188,22 +263,31 @@ func main() {
         return
     }
 
-    var components []Component
-
-    // Extract from core collector
-    coreComponents := extractFromPath(*collectorPath, false)
-    components = append(components, coreComponents...)
+    cache, err := newExtractCache(*cacheDir)
+    if err != nil {
+        fmt.Printf("Extraction cache disabled: %v\n", err)
+    }
 
-    // Extract from contrib
-    contribComponents := extractFromPath(*contribPath, true)
-    components = append(components, contribComponents...)
+    // Core collector components first, then contrib
+    tasks := append(componentTasks(*collectorPath, false), componentTasks(*contribPath, true)...)
+    components := extractTasks(tasks, cache, *collectorPath, *contribPath)
+    var releases []string
+    if *releasesPath != "" {
+        var byModule map[string][]string
+        byModule, releases = readReleaseManifests(*releasesPath)
+        applyReleaseDistributions(components, byModule)
+    }
 
     result := ExtractedData{
         Version:    *version,
         Components: components,
         Document:   buildDocumentSchema(),
-        Definitions: nil,
+        Service:    extractServiceComponent(*collectorPath),
+        Releases:   releases,
+        FeatureGates: extractFeatureGates(components, *collectorPath, *contribPath),
     }
+    applyServiceTelemetry(&result.Document, result.Service)
+    factorDefinitions(&result)
 
     // Save to JSON
     data, err := json.MarshalIndent(result, "", "  ")
@@ -219,17 +303,30 @@ func main() {
     fmt.Printf("Extracted %d components to %s\n", len(components), *output)
 }
 
-func extractFromPath(basePath string, isContrib bool) []Component {
-    var components []Component
-
-    type task struct {
-        componentPath string
-        name          string
-        typ           string
-        isContrib     bool
+// loadExtractedData reads an ExtractedData JSON document written by the extractor, with
+// "$ref" fields expanded so every component lists its full field set.
+func loadExtractedData(path string) (*ExtractedData, error) {
+    data, err := os.ReadFile(path)
+    if err != nil { return nil, err }
+    var d ExtractedData
+    if err := json.Unmarshal(data, &d); err != nil {
+        return nil, fmt.Errorf("parse %s: %w", path, err)
     }
+    if err := expandDefinitions(&d); err != nil { return nil, fmt.Errorf("%s: %w", path, err) }
+    return &d, nil
+}
+
+// extractTask is one component directory to extract.
+type extractTask struct {
+    componentPath string
+    name          string
+    typ           string
+    isContrib     bool
+}
 
-    var tasks []task
+// componentTasks lists the component directories (those with a config.go) under a repo.
+func componentTasks(basePath string, isContrib bool) []extractTask {
+    var tasks []extractTask
     componentTypes := []string{"receiver", "processor", "exporter", "extension", "connector"}
     for _, componentType := range componentTypes {
         typePath := filepath.Join(basePath, componentType)
@@ -244,43 +341,71 @@ func extractFromPath(basePath string, isContrib bool) []Component {
             if _, err := os.Stat(filepath.Join(componentPath, "config.go")); err != nil {
                 continue
             }
-            tasks = append(tasks, task{componentPath: componentPath, name: e.Name(), typ: componentType, isContrib: isContrib})
+            tasks = append(tasks, extractTask{componentPath: componentPath, name: e.Name(), typ: componentType, isContrib: isContrib})
+        }
+    }
+    return tasks
+}
+
+// extractTasks extracts components in a worker pool, reusing cached results for
+// components whose sources are unchanged. The package cache is only pre-warmed when
+// something has to be extracted. Results keep task order.
+func extractTasks(tasks []extractTask, cache *extractCache, repos ...string) []Component {
+    results := make([]*Component, len(tasks))
+    keys := make([]string, len(tasks))
+    var pending []int
+    for i, t := range tasks {
+        if cache != nil {
+            key, err := cache.key(t)
+            if err != nil {
+                dbgf("[extractor] warn: cache key for %s: %v\n", t.componentPath, err)
+            } else if c, ok := cache.load(key); ok {
+                results[i] = c
+                continue
+            }
+            keys[i] = key
         }
+        pending = append(pending, i)
+    }
+    if cache != nil {
+        fmt.Printf("Extraction cache: %d reused, %d to extract\n", len(tasks)-len(pending), len(pending))
+    }
+
+    if len(pending) > 0 {
+        // Pre-warm global package cache for the repos to speed up lookups
+        for _, r := range repos { prewarmPackageCache(r) }
     }
 
     // Worker pool
     workers := runtime.NumCPU()
     if workers < 2 { workers = 2 }
-    in := make(chan task)
-    out := make(chan *Component)
+    in := make(chan int)
 
     var wg sync.WaitGroup
     worker := func() {
         defer wg.Done()
-        for t := range in {
+        for i := range in {
+            t := tasks[i]
             dbgf("[extractor] scanning %s/%s\n", t.typ, t.name)
             c := extractComponent(t.componentPath, t.name, t.typ, t.isContrib)
-            out <- c
+            if c != nil {
+                dbgf("[extractor] ✓ extracted %s/%s fields=%d constraints=%d\n",
+                    c.Type, c.Name, len(c.Config.Fields), len(c.Constraints))
+                if cache != nil && keys[i] != "" { cache.store(keys[i], c) }
+            }
+            results[i] = c
         }
     }
     wg.Add(workers)
     for i := 0; i < workers; i++ { go worker() }
+    for _, i := range pending { in <- i }
+    close(in)
+    wg.Wait()
 
-    go func() {
-        for _, t := range tasks { in <- t }
-        close(in)
-        wg.Wait()
-        close(out)
-    }()
-
-    for c := range out {
-        if c != nil {
-            components = append(components, *c)
-            dbgf("[extractor] ✓ extracted %s/%s fields=%d constraints=%d\n",
-                c.Type, c.Name, len(c.Config.Fields), len(c.Constraints))
-        }
+    var components []Component
+    for _, c := range results {
+        if c != nil { components = append(components, *c) }
     }
-
     return components
 }
 
@@ -331,22 +456,7 @@ func extractComponent(componentPath, name, componentType string, isContrib bool)
     dbgf("[extractor] root=%s fields=%d\n", configSchema.StructName, len(configSchema.Fields))
 
     // Extract defaults from factory (deep) using parsed AST
-    defaults := extractDefaultsDeepWithAST(componentPath, configPath, fset, factoryAST)
-    // Apply defaults onto matching fields and clear required for those fields
-    if len(defaults) > 0 {
-        defByKey := map[string]interface{}{}
-        for _, d := range defaults {
-            defByKey[d.YamlKey] = d.Value
-        }
-        for i := range configSchema.Fields {
-            if v, ok := defByKey[configSchema.Fields[i].MapStructure]; ok {
-                configSchema.Fields[i].Default = v
-                configSchema.Fields[i].Required = false
-            }
-        }
-        // Normalize enum defaults to YAML tokens now that defaults are applied
-        configSchema.Fields = normalizeEnumDefaults(configSchema.Fields)
-    }
+    applyDefaults(configSchema, extractDefaultsDeepWithAST(componentPath, configPath, fset, factoryAST))
 
     // Build module path
     modulePath := fmt.Sprintf("go.opentelemetry.io/collector/%s/%s", componentType, name)
@@ -373,9 +483,19 @@ func extractComponent(componentPath, name, componentType string, isContrib bool)
         Module: modulePath,
         Config: *configSchema,
     }
+    for _, f := range component.Config.Fields {
+        if f.CustomUnmarshal { component.CustomUnmarshal = true; break }
+    }
     // Attach constraints derived from validation
     constraints := analyzeConstraints(componentPath, configPath)
     component.Constraints = constraints
+    // Supported signals from factory options
+    component.Signals, component.SignalPairs, component.Stability = extractSignals(componentPath)
+    // Description, distributions, emitted metrics, ... from metadata.yaml
+    applyComponentMetadata(component, readComponentMetadata(componentPath))
+    if component.Description == "" {
+        if ctx, err := loadPackage(componentPath, "."); err == nil { component.Description = packageDocSummary(ctx) }
+    }
     // Collect examples from example/examples/testdata folders
     component.Config.Examples = gatherExamples(componentPath)
     return component
@@ -448,6 +568,82 @@ func componentIDFromFactoryAST(node *ast.File) string {
     return result
 }
 
+// Factory options naming a signal (WithTraces) or a connector pair (WithTracesToMetrics).
+var (
+    signalOptionRe     = regexp.MustCompile(`^With(Traces|Metrics|Logs|Profiles)$`)
+    signalPairOptionRe = regexp.MustCompile(`^With(Traces|Metrics|Logs|Profiles)To(Traces|Metrics|Logs|Profiles)$`)
+)
+
+// extractSignals scans the component package for factory options such as
+// receiver.WithTraces(...) or connector.WithTracesToMetrics(...), along with the
+// stability level passed to each (keyed like metadata.yaml: "traces", "traces_to_metrics").
+func extractSignals(componentDir string) ([]string, []SignalPair, map[string]string) {
+    ctx, err := loadPackage(componentDir, ".")
+    if err != nil { return nil, nil, nil }
+    var signals []string
+    var pairs []SignalPair
+    seenPair := map[SignalPair]bool{}
+    stability := map[string]string{}
+    optionStability := func(call *ast.CallExpr, key string) {
+        if len(call.Args) < 2 { return }
+        if level := stabilityFromExpr(ctx, call.Args[1], 0); level != "" { stability[key] = level }
+    }
+    for _, file := range ctx.files {
+        ast.Inspect(file, func(n ast.Node) bool {
+            call, ok := n.(*ast.CallExpr)
+            if !ok { return true }
+            sel, ok := call.Fun.(*ast.SelectorExpr)
+            if !ok { return true }
+            if m := signalOptionRe.FindStringSubmatch(sel.Sel.Name); m != nil {
+                signal := strings.ToLower(m[1])
+                signals = append(signals, signal)
+                optionStability(call, signal)
+            } else if m := signalPairOptionRe.FindStringSubmatch(sel.Sel.Name); m != nil {
+                p := SignalPair{From: strings.ToLower(m[1]), To: strings.ToLower(m[2])}
+                if !seenPair[p] {
+                    seenPair[p] = true
+                    pairs = append(pairs, p)
+                }
+                optionStability(call, p.From+"_to_"+p.To)
+            } else if sel.Sel.Name == "NewFactory" && len(call.Args) >= 4 {
+                // extension.NewFactory(type, createDefaultConfig, create, stability)
+                if level := stabilityFromExpr(ctx, call.Args[len(call.Args)-1], 0); level != "" { stability["extension"] = level }
+            }
+            return true
+        })
+    }
+    if len(stability) == 0 { stability = nil }
+    sort.Slice(pairs, func(i, j int) bool {
+        if pairs[i].From != pairs[j].From { return pairs[i].From < pairs[j].From }
+        return pairs[i].To < pairs[j].To
+    })
+    return uniqueSorted(signals), pairs, stability
+}
+
+// stabilityFromExpr resolves a stability argument such as component.StabilityLevelBeta,
+// or a constant like metadata.TracesStability defined in the generated metadata package,
+// to its level name ("beta"). Returns "" when the expression cannot be resolved.
+func stabilityFromExpr(ctx *packageContext, expr ast.Expr, depth int) string {
+    if ctx == nil || depth > 4 { return "" }
+    switch e := expr.(type) {
+    case *ast.SelectorExpr:
+        if strings.HasPrefix(e.Sel.Name, "StabilityLevel") {
+            level := strings.ToLower(strings.TrimPrefix(e.Sel.Name, "StabilityLevel"))
+            if level == "undefined" { return "" }
+            return level
+        }
+        pkgIdent, ok := e.X.(*ast.Ident)
+        if !ok { return "" }
+        importPath, ok := ctx.imports[pkgIdent.Name]
+        if !ok { return "" }
+        pkg := resolveExternalPackage(ctx, importPath)
+        if v := topLevelValueExpr(pkg, e.Sel.Name); v != nil { return stabilityFromExpr(pkg, v, depth+1) }
+    case *ast.Ident:
+        if v := topLevelValueExpr(ctx, e.Name); v != nil { return stabilityFromExpr(ctx, v, depth+1) }
+    }
+    return ""
+}
+
 // findRootConfigTypeFromFactory returns the struct type used in
 // createDefaultConfig (e.g., "Config"). This is our best signal for the
 // actual root config type when multiple *Config types exist.
@@ -524,6 +720,23 @@ func callArgString(call *ast.CallExpr) (string, bool) {
 
 // --- Recursive schema extraction ---
 
+// applyDefaults sets factory defaults onto matching fields and clears required for those fields.
+func applyDefaults(schema *ConfigSchema, defaults []DefaultValue) {
+    if len(defaults) == 0 { return }
+    defByKey := map[string]interface{}{}
+    for _, d := range defaults {
+        defByKey[d.YamlKey] = d.Value
+    }
+    for i := range schema.Fields {
+        if v, ok := defByKey[schema.Fields[i].MapStructure]; ok {
+            schema.Fields[i].Default = v
+            schema.Fields[i].Required = false
+        }
+    }
+    // Normalize enum defaults to YAML tokens now that defaults are applied
+    schema.Fields = normalizeEnumDefaults(schema.Fields)
+}
+
 func extractConfigSchemaRecursive(componentDir string, configPath string, preferredRoot string) (*ConfigSchema, error) {
     pkgCtx, err := loadPackage(componentDir, ".")
     if err != nil {
@@ -590,8 +803,11 @@ func extractConfigSchemaRecursive(componentDir string, configPath string, prefer
     extractStructFields(rootCtx, rootStruct, "", &fields, visited)
     // Augment with Validate() insights (field-level) from the owning package
     applyValidationHeuristics(componentDir, rootCtx, rootName, &fields)
+    // Deprecated keys from Validate()/Unmarshal() messages; resolves replacement names
+    applyDeprecationHints(rootCtx, rootName, fields)
     // Post-process fields: collapse arrays-of-components and add hints/tokens
     schema.Fields = postProcessFields(fields)
+    applyFieldOrigins(pkgCtx.path, schema.Fields)
     return schema, nil
 }
 
@@ -618,6 +834,14 @@ func extractStructFields(ctx *packageContext, st *ast.StructType, prefix string,
     if visited[key] > 0 { return }
     visited[key]++
     defer func() { visited[key]--; if visited[key] <= 0 { delete(visited, key) } }()
+    // Structs with their own Unmarshal/UnmarshalText accept shapes the fields do not describe
+    start := len(*out)
+    if name := structTypeName(ctx, st); name != "" {
+        defer func() { recordOrigin(ctx, name, prefix, (*out)[start:]) }()
+    }
+    if cu := findCustomUnmarshal(ctx, structTypeName(ctx, st)); cu != nil {
+        defer func() { markCustomUnmarshal(cu, prefix, (*out)[start:], out) }()
+    }
     for _, f := range st.Fields.List {
         // Determine tags and mapstructure
         tagValue := ""
@@ -629,9 +853,20 @@ func extractStructFields(ctx *packageContext, st *ast.StructType, prefix string,
         validateTag := tag.Get("validate")
         hasSquash := strings.Contains(mapstruct, "squash")
 
+        // mdatagen-generated metric/resource attribute toggles are expanded from metadata.yaml
+        if mdatagenConfigTypes[typeNameFromExpr(f.Type)] {
+            mdPrefix := prefix
+            if token := strings.Split(mapstruct, ",")[0]; token != "" && !hasSquash {
+                mdPrefix = strings.TrimPrefix(prefix+"."+token, ".")
+            } else if token == "" && !hasSquash && len(f.Names) > 0 {
+                mdPrefix = strings.TrimPrefix(prefix+"."+guessYAMLTokenFromGoName(f.Names[0].Name), ".")
+            }
+            if expandMdatagenConfig(ctx, f.Type, mdPrefix, out) { continue }
+        }
+
         // Embedded (anonymous) field handling
         if len(f.Names) == 0 {
-            nextCtx, target := resolveStructFromExprWithCtx(ctx, f.Type)
+            nextCtx, target := resolveFieldStruct(ctx, f.Type)
             if target != nil {
                 // If anonymous has a mapstructure name (and not squash), treat it as a nested namespace
                 var nextPrefix = prefix
@@ -645,7 +880,7 @@ func extractStructFields(ctx *packageContext, st *ast.StructType, prefix string,
         }
         // Named field with squash: inline
         if hasSquash {
-            nextCtx, target := resolveStructFromExprWithCtx(ctx, f.Type)
+            nextCtx, target := resolveFieldStruct(ctx, f.Type)
             if target != nil {
                 extractStructFields(nextCtx, target, prefix, out, visited)
             }
@@ -669,11 +904,16 @@ func extractStructFields(ctx *packageContext, st *ast.StructType, prefix string,
 
         // If struct-like, recurse; otherwise add as leaf
         if isStructLike(f.Type) {
-            nextCtx, target := resolveStructFromExprWithCtx(ctx, f.Type)
+            nextCtx, target := resolveFieldStruct(ctx, f.Type)
             // Optional debug for single-component runs
             dbgf("DBG %s field type=%T\n", fullKey, f.Type)
             if target != nil {
+                before := len(*out)
                 extractStructFields(nextCtx, target, fullKey, out, visited)
+                // A deprecated section deprecates everything below it
+                if dep, _, _ := deprecationFromDoc(f); dep {
+                    for i := before; i < len(*out); i++ { (*out)[i].Deprecated = true }
+                }
                 continue
             }
         }
@@ -701,8 +941,14 @@ func extractStructFields(ctx *packageContext, st *ast.StructType, prefix string,
             Required:     required,
             PathTokens:   makePathTokens(fullKey),
         }
-        // Enum extraction
-        if swiftType == "enum" {
+        cf.Deprecated, cf.Replacement, cf.replacementQuoted = deprecationFromDoc(f)
+        // Kind and enum extraction
+        if kind, itemType, enumValues, ok := typedFieldKind(ctx, f.Type); ok {
+            // --typed: the checked type decides; no name-based guessing
+            cf.Type = kind
+            cf.ItemType = itemType
+            cf.EnumValues = enumValues
+        } else if swiftType == "enum" {
             cf.EnumValues = inferEnumValues(ctx, f.Type, comment, goType)
         } else if swiftType == "custom" {
             // Only consider named custom types with declared constants as enums (e.g., string-typed aliases)
@@ -711,10 +957,74 @@ func extractStructFields(ctx *packageContext, st *ast.StructType, prefix string,
                 cf.EnumValues = vals
             }
         }
+        // Slices and maps of structs: the element fields follow under "[]" / "{key}" placeholders
+        layers, elemCtx, elemStruct := collectionElemStruct(ctx, f.Type)
+        if elemStruct != nil {
+            cf.Type = collectionKind(layers[0])
+            cf.ItemType = "object"
+            if len(layers) > 1 { cf.ItemType = collectionKind(layers[1]) }
+            cf.EnumValues = nil
+        }
         // Hints: format/unit/sensitive
         annotateFieldHints(&cf)
         *out = append(*out, cf)
+        if elemStruct != nil {
+            extractStructFields(elemCtx, elemStruct, fullKey+"."+strings.Join(layers, "."), out, visited)
+        }
+    }
+}
+
+// collectionElemStruct peels slice and map layers off a field type, following named
+// types such as `type Routes []Route`, and resolves the element struct. layers holds one
+// "[]" or "{key}" placeholder per level. The struct is nil for non-struct elements and for
+// structs without exported fields (e.g. component.ID, which unmarshals from a string).
+func collectionElemStruct(ctx *packageContext, expr ast.Expr) ([]string, *packageContext, *ast.StructType) {
+    var layers []string
+    cur, curCtx := expr, ctx
+peel:
+    for depth := 0; depth < 8; depth++ {
+        switch t := cur.(type) {
+        case *ast.StarExpr:
+            cur = t.X
+        case *ast.ArrayType:
+            layers = append(layers, "[]")
+            cur = t.Elt
+        case *ast.MapType:
+            layers = append(layers, "{key}")
+            cur = t.Value
+        case *ast.Ident, *ast.SelectorExpr:
+            nctx, _, underlying := resolveNamedType(curCtx, cur)
+            switch underlying.(type) {
+            case *ast.ArrayType, *ast.MapType:
+                cur, curCtx = underlying, nctx
+            default:
+                break peel
+            }
+        default:
+            break peel
+        }
+    }
+    if len(layers) == 0 { return nil, nil, nil }
+    nextCtx, st := resolveFieldStruct(curCtx, cur)
+    if st == nil || !hasExportedFields(st) { return layers, nil, nil }
+    return layers, nextCtx, st
+}
+
+// collectionKind maps a collection placeholder to its field type.
+func collectionKind(layer string) string {
+    if layer == "{key}" { return "map" }
+    return "array"
+}
+
+func hasExportedFields(st *ast.StructType) bool {
+    if st.Fields == nil { return false }
+    for _, f := range st.Fields.List {
+        if len(f.Names) == 0 { return true }
+        for _, n := range f.Names {
+            if n.IsExported() { return true }
+        }
     }
+    return false
 }
 
 // Replace generic array element placeholders in paths with a readable token.
@@ -903,7 +1213,8 @@ func resolveNamedType(ctx *packageContext, expr ast.Expr) (*packageContext, stri
 
 // Extract enum tokens by scanning const declarations for the given named type.
 // - For string-typed enums, use the literal values.
-// - For numeric enums, derive tokens from constant identifiers by stripping the type name prefix and lowercasing.
+// - For numeric enums decoded by an UnmarshalText method, derive tokens from constant identifiers
+//   (see constantToken), in declaration order. Other numeric types decode from integers only.
 func extractEnumValuesFromType(ctx *packageContext, typeExpr ast.Expr, goType string) []string {
     pkg, typeName, underlying := resolveNamedType(ctx, typeExpr)
     if pkg == nil || typeName == "" {
@@ -919,6 +1230,7 @@ func extractEnumValuesFromType(ctx *packageContext, typeExpr ast.Expr, goType st
         isString = false
     }
 
+    if !isString && !hasUnmarshalText(pkg, typeName) { return nil }
     // Scan constants in the defining package
     tokens := []string{}
     seen := map[string]struct{}{}
@@ -966,11 +1278,7 @@ func extractEnumValuesFromType(ctx *packageContext, typeExpr ast.Expr, goType st
                         }
                         // If string-typed constant has no literal, skip; do not fallback to identifier.
                     } else {
-                        if tok == "" {
-                            // Fallback: derive from constant identifier (e.g., LevelBasic -> basic)
-                            tok = strings.ToLower(strings.TrimPrefix(name.Name, typeName))
-                            tok = strings.TrimPrefix(tok, "_")
-                        }
+                        tok = constantToken(name.Name, typeName)
                     }
                     // Filter empty tokens
                     if tok == "" { continue }
@@ -982,8 +1290,8 @@ func extractEnumValuesFromType(ctx *packageContext, typeExpr ast.Expr, goType st
             }
         }
     }
-    // Keep stable order
-    for i := 1; i < len(tokens); i++ {
+    // Keep stable order; numeric constants keep their declaration (value) order
+    for i := 1; isString && i < len(tokens); i++ {
         j := i
         for j > 0 && tokens[j] < tokens[j-1] {
             tokens[j], tokens[j-1] = tokens[j-1], tokens[j]
@@ -993,18 +1301,52 @@ func extractEnumValuesFromType(ctx *packageContext, typeExpr ast.Expr, goType st
     return tokens
 }
 
+// constantToken derives the text form of a numeric enum constant from its identifier:
+// LevelBasic -> basic (type name prefix), DebugLevel -> debug (suffix, as in zapcore).
+func constantToken(name, typeName string) string {
+    if strings.HasPrefix(name, typeName) {
+        name = strings.TrimPrefix(name, typeName)
+    } else {
+        name = strings.TrimSuffix(name, typeName)
+    }
+    return strings.Trim(strings.ToLower(name), "_")
+}
+
+// hasUnmarshalText reports whether typeName declares an UnmarshalText method in pkg.
+func hasUnmarshalText(pkg *packageContext, typeName string) bool {
+    for _, f := range pkg.files {
+        for _, d := range f.Decls {
+            fd, ok := d.(*ast.FuncDecl)
+            if !ok || fd.Recv == nil || len(fd.Recv.List) == 0 || fd.Name.Name != "UnmarshalText" { continue }
+            recv := fd.Recv.List[0].Type
+            if star, ok := recv.(*ast.StarExpr); ok { recv = star.X }
+            if id, ok := recv.(*ast.Ident); ok && id.Name == typeName { return true }
+        }
+    }
+    return false
+}
+
 // Collapse noisy array tokenizations like "auth.authenticator.[]" and
-// "auth.authenticator.[].name" into a single array field with itemType hints.
+// "auth.authenticator.[].name" into a single array field with itemType hints,
+// unless the array was extracted with an element schema.
 func postProcessFields(fields []ConfigField) []ConfigField {
     if len(fields) == 0 { return fields }
     // Index by normalized prefix before []
     type agg struct { idxs []int }
     buckets := map[string]*agg{}
+    // Collections with an extracted element schema keep their item fields
+    declared := map[string]bool{}
+    for i := range fields {
+        if fields[i].ItemType != "" && (fields[i].Type == "array" || fields[i].Type == "map") {
+            declared[strings.Join(fields[i].PathTokens, ".")] = true
+        }
+    }
     for i := range fields {
         ft := fields[i].PathTokens
         for j := range ft {
             if ft[j] == "[]" {
                 prefix := strings.Join(ft[:j], ".")
+                if declared[prefix] { break }
                 if prefix == "" { prefix = fields[i].MapStructure }
                 if buckets[prefix] == nil { buckets[prefix] = &agg{} }
                 buckets[prefix].idxs = append(buckets[prefix].idxs, i)
@@ -1091,6 +1433,11 @@ func buildDocumentSchema() DocumentSchema {
     d.PipelineShape.Processors = true
     d.PipelineShape.Exporters = true
     d.PipelineShape.Connectors = true
+    d.PipelineRefs = map[string][]string{
+        "receivers":  {"receivers", "connectors"},
+        "processors": {"processors"},
+        "exporters":  {"exporters", "connectors"},
+    }
     d.Telemetry.MetricsLevels = []string{"none", "basic", "normal", "detailed"}
     d.Telemetry.DefaultLevel = "basic"
     return d
@@ -1157,42 +1504,13 @@ func loadPackage(dir string, pattern string) (*packageContext, error) {
         globalPkgCache.mu.RUnlock()
     }
 
-    cfg := &packages.Config{Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax, Dir: dir}
+    cfg := &packages.Config{Mode: packagesLoadMode(), Dir: dir}
     pkgs, err := packages.Load(cfg, pattern)
     if err != nil { return nil, err }
     if len(pkgs) == 0 { return nil, fmt.Errorf("no packages for %s in %s", pattern, dir) }
     p := pkgs[0]
-    files := p.Syntax
-    fset := p.Fset
-    imports := map[string]string{}
-    types := map[string]*ast.StructType{}
-    aliases := map[string]ast.Expr{}
-    for _, file := range files {
-        for _, is := range file.Imports {
-            path := strings.Trim(is.Path.Value, "\"")
-            alias := ""
-            if is.Name != nil { alias = is.Name.Name } else {
-                parts := strings.Split(path, "/")
-                alias = parts[len(parts)-1]
-            }
-            imports[alias] = path
-        }
-        for _, decl := range file.Decls {
-            gd, ok := decl.(*ast.GenDecl)
-            if !ok || gd.Tok != token.TYPE { continue }
-            for _, spec := range gd.Specs {
-                ts, ok := spec.(*ast.TypeSpec)
-                if !ok { continue }
-                switch tt := ts.Type.(type) {
-                case *ast.StructType:
-                    types[ts.Name.Name] = tt
-                default:
-                    aliases[ts.Name.Name] = tt
-                }
-            }
-        }
-    }
-    pc := &packageContext{dir: dir, files: files, fset: fset, imports: imports, types: types, aliases: aliases, importCache: map[string]*packageContext{}}
+    pc := newPackageContext(p, dir)
+    if *typed { cacheTypedDeps(p) }
     // Update global cache
     globalPkgCache.mu.Lock()
     // best-effort mapping by dir
@@ -1267,6 +1585,13 @@ func resolveStructFromExprWithCtx(ctx *packageContext, expr ast.Expr) (*packageC
     }
 }
 
+// resolveFieldStruct resolves a struct field's type to its struct declaration,
+// using go/types when --typed loaded type information and the AST otherwise.
+func resolveFieldStruct(ctx *packageContext, expr ast.Expr) (*packageContext, *ast.StructType) {
+    if pc, st, known := typedStructTarget(ctx, expr); known { return pc, st }
+    return resolveStructFromExprWithCtx(ctx, expr)
+}
+
 // Backward-compatible thin wrapper for callers that only need the struct.
 func resolveStructFromExpr(ctx *packageContext, expr ast.Expr) *ast.StructType {
     _, st := resolveStructFromExprWithCtx(ctx, expr)
@@ -1316,52 +1641,31 @@ func prewarmPackageCache(root string) {
     if root == "" { return }
     st, err := os.Stat(root)
     if err != nil || !st.IsDir() { return }
-    cfg := &packages.Config{Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax, Dir: root}
+    cfg := &packages.Config{Mode: packagesLoadMode(), Dir: root}
     // Load all subpackages
     pkgs, err := packages.Load(cfg, "./...")
     if err != nil || len(pkgs) == 0 { return }
-    for _, p := range pkgs {
-        files := p.Syntax
-        fset := p.Fset
-        imports := map[string]string{}
-        types := map[string]*ast.StructType{}
-        aliases := map[string]ast.Expr{}
-        for _, file := range files {
-            for _, is := range file.Imports {
-                path := strings.Trim(is.Path.Value, "\"")
-                alias := ""
-                if is.Name != nil { alias = is.Name.Name } else {
-                    parts := strings.Split(path, "/")
-                    alias = parts[len(parts)-1]
-                }
-                imports[alias] = path
-            }
-            for _, decl := range file.Decls {
-                if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.TYPE {
-                    for _, spec := range gd.Specs {
-                        if ts, ok := spec.(*ast.TypeSpec); ok {
-                            switch tt := ts.Type.(type) {
-                            case *ast.StructType:
-                                types[ts.Name.Name] = tt
-                            default:
-                                aliases[ts.Name.Name] = tt
-                            }
-                        }
-                    }
-                }
-            }
-        }
+    register := func(p *packages.Package) {
         // Determine dir for this package
         dir := root
         if len(p.GoFiles) > 0 {
             dir = filepath.Dir(p.GoFiles[0])
         }
-        pc := &packageContext{dir: dir, files: files, fset: fset, imports: imports, types: types, aliases: aliases, importCache: map[string]*packageContext{}}
+        pc := newPackageContext(p, dir)
         globalPkgCache.mu.Lock()
         if p.PkgPath != "" { globalPkgCache.byImport[p.PkgPath] = pc }
         globalPkgCache.byDir[dir] = pc
         globalPkgCache.mu.Unlock()
     }
+    if !*typed {
+        for _, p := range pkgs { register(p) }
+        return
+    }
+    // Typed loads include dependencies (with syntax and type info); index them as well so
+    // external struct lookups resolve without another type-checking load.
+    packages.Visit(pkgs, nil, func(p *packages.Package) {
+        if len(p.Syntax) > 0 { register(p) }
+    })
 }
 
 // --- Validation analysis (best-effort) ---
@@ -1786,8 +2090,10 @@ func extractType(expr ast.Expr) string {
 }
 
 func mapGoTypeToSwift(goType string) string {
-    // Basic type mappings
+    // Basic type mappings; optional values (*int, *string as in otelconf) map like their element
     switch {
+    case strings.HasPrefix(goType, "*"):
+        return mapGoTypeToSwift(goType[1:])
     case goType == "string":
         return "string"
     case goType == "bool":
@@ -2480,6 +2786,25 @@ func tryDurationString(be *ast.BinaryExpr) string {
     }
 }
 
+// topLevelValueExpr returns the initializer expression of a package-level constant or var.
+func topLevelValueExpr(ctx *packageContext, name string) ast.Expr {
+    if ctx == nil { return nil }
+    for _, f := range ctx.files {
+        for _, d := range f.Decls {
+            gd, ok := d.(*ast.GenDecl)
+            if !ok || (gd.Tok != token.CONST && gd.Tok != token.VAR) { continue }
+            for _, s := range gd.Specs {
+                vs, ok := s.(*ast.ValueSpec)
+                if !ok { continue }
+                for i, n := range vs.Names {
+                    if n.Name == name && i < len(vs.Values) { return vs.Values[i] }
+                }
+            }
+        }
+    }
+    return nil
+}
+
 // resolveTopLevelIdent tries to resolve a package-level constant or var to a literal value.
 func resolveTopLevelIdent(ctx *packageContext, name string) (interface{}, bool) {
     for _, f := range ctx.files {
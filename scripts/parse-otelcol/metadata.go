@@ -0,0 +1,223 @@
+package main
+
+import (
+    "go/ast"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+
+    "gopkg.in/yaml.v3"
+)
+
+// componentMetadata is the part of a component's mdatagen metadata.yaml used by the extractor.
+type componentMetadata struct {
+    Type        string `yaml:"type"`
+    DisplayName string `yaml:"display_name"`
+    Description string `yaml:"description"`
+    Status      struct {
+        Class         string              `yaml:"class"`
+        Stability     map[string][]string `yaml:"stability"` // level -> signals, e.g. beta: [traces, metrics]
+        Distributions []string            `yaml:"distributions"`
+        Codeowners    struct {
+            Active []string `yaml:"active"`
+        } `yaml:"codeowners"`
+    } `yaml:"status"`
+    Attributes         map[string]metadataAttribute `yaml:"attributes"`
+    ResourceAttributes map[string]metadataAttribute `yaml:"resource_attributes"`
+    Metrics            map[string]metadataMetric    `yaml:"metrics"`
+}
+
+type metadataAttribute struct {
+    Description  string   `yaml:"description"`
+    Type         string   `yaml:"type"`
+    NameOverride string   `yaml:"name_override"`
+    Enum         []string `yaml:"enum"`
+    Enabled      bool     `yaml:"enabled"` // resource attributes only
+}
+
+type metadataMetric struct {
+    Enabled     bool      `yaml:"enabled"`
+    Description string    `yaml:"description"`
+    Unit        string    `yaml:"unit"`
+    Gauge       *struct{} `yaml:"gauge"`
+    Sum         *struct{} `yaml:"sum"`
+    Histogram   *struct{} `yaml:"histogram"`
+    Attributes  []string  `yaml:"attributes"`
+}
+
+// EmittedMetric is a metric a component can produce, as declared in metadata.yaml.
+type EmittedMetric struct {
+    Name        string            `json:"name"`
+    Description string            `json:"description,omitempty"`
+    Unit        string            `json:"unit,omitempty"`
+    Type        string            `json:"type,omitempty"` // gauge, sum, histogram
+    Enabled     bool              `json:"enabled"`
+    Attributes  []MetricAttribute `json:"attributes,omitempty"`
+}
+
+// MetricAttribute is a data point attribute of an emitted metric.
+type MetricAttribute struct {
+    Name        string   `json:"name"`
+    Description string   `json:"description,omitempty"`
+    Type        string   `json:"type,omitempty"`
+    Enum        []string `json:"enum,omitempty"`
+}
+
+// ResourceAttribute is a resource attribute a component can set, with its default toggle.
+type ResourceAttribute struct {
+    Name        string   `json:"name"`
+    Description string   `json:"description,omitempty"`
+    Type        string   `json:"type,omitempty"`
+    Enum        []string `json:"enum,omitempty"`
+    Enabled     bool     `json:"enabled"`
+}
+
+// readComponentMetadata parses <componentDir>/metadata.yaml; nil when absent or malformed.
+func readComponentMetadata(componentDir string) *componentMetadata {
+    data, err := os.ReadFile(filepath.Join(componentDir, "metadata.yaml"))
+    if err != nil { return nil }
+    var md componentMetadata
+    if err := yaml.Unmarshal(data, &md); err != nil {
+        dbgf("[extractor] warn: %s/metadata.yaml: %v\n", componentDir, err)
+        return nil
+    }
+    return &md
+}
+
+// stabilityBySignal inverts status.stability into signal -> level.
+func (md *componentMetadata) stabilityBySignal() map[string]string {
+    out := map[string]string{}
+    if md == nil { return out }
+    for level, signals := range md.Status.Stability {
+        for _, s := range signals { out[s] = level }
+    }
+    return out
+}
+
+// emittedMetrics lists metadata.yaml metrics sorted by name, resolving attribute references.
+func (md *componentMetadata) emittedMetrics() []EmittedMetric {
+    if md == nil { return nil }
+    var out []EmittedMetric
+    for _, name := range sortedKeys(md.Metrics) {
+        m := md.Metrics[name]
+        em := EmittedMetric{Name: name, Description: strings.TrimSpace(m.Description), Unit: m.Unit, Enabled: m.Enabled}
+        switch {
+        case m.Gauge != nil:
+            em.Type = "gauge"
+        case m.Sum != nil:
+            em.Type = "sum"
+        case m.Histogram != nil:
+            em.Type = "histogram"
+        }
+        for _, ref := range m.Attributes {
+            a := md.Attributes[ref]
+            attrName := ref
+            if a.NameOverride != "" { attrName = a.NameOverride }
+            em.Attributes = append(em.Attributes, MetricAttribute{Name: attrName, Description: strings.TrimSpace(a.Description), Type: a.Type, Enum: a.Enum})
+        }
+        out = append(out, em)
+    }
+    return out
+}
+
+// resourceAttributes lists metadata.yaml resource_attributes sorted by name.
+func (md *componentMetadata) resourceAttributes() []ResourceAttribute {
+    if md == nil { return nil }
+    var out []ResourceAttribute
+    for _, name := range sortedKeys(md.ResourceAttributes) {
+        a := md.ResourceAttributes[name]
+        out = append(out, ResourceAttribute{Name: name, Description: strings.TrimSpace(a.Description), Type: a.Type, Enum: a.Enum, Enabled: a.Enabled})
+    }
+    return out
+}
+
+// applyComponentMetadata copies metadata.yaml information onto an extracted component.
+func applyComponentMetadata(c *Component, md *componentMetadata) {
+    for sig, level := range md.stabilityBySignal() {
+        if c.Stability == nil { c.Stability = map[string]string{} }
+        // Factory-declared levels win; metadata.yaml fills in the rest
+        if _, ok := c.Stability[sig]; !ok { c.Stability[sig] = level }
+    }
+    if md == nil { return }
+    if c.Description == "" { c.Description = strings.TrimSpace(md.Description) }
+    c.Distributions = append([]string(nil), md.Status.Distributions...)
+    sort.Strings(c.Distributions)
+    c.Codeowners = append([]string(nil), md.Status.Codeowners.Active...)
+    c.Metrics = md.emittedMetrics()
+    c.ResourceAttributes = md.resourceAttributes()
+}
+
+// packageDocSummary returns the first paragraph of the package doc comment
+// (e.g. "Package otlpreceiver receives data in OTLP format."), or "".
+func packageDocSummary(ctx *packageContext) string {
+    if ctx == nil { return "" }
+    var doc *ast.CommentGroup
+    for _, f := range ctx.files {
+        if f.Doc == nil { continue }
+        // Prefer doc.go, which by convention carries the package documentation
+        if doc == nil || strings.HasSuffix(ctx.fset.Position(f.Package).Filename, "doc.go") { doc = f.Doc }
+    }
+    if doc == nil { return "" }
+    text := strings.TrimSpace(doc.Text())
+    if i := strings.Index(text, "\n\n"); i >= 0 { text = text[:i] }
+    return strings.Join(strings.Fields(text), " ")
+}
+
+// mdatagenConfigTypes are the structs mdatagen generates in internal/metadata for
+// `metrics.<name>.enabled` and `resource_attributes.<name>.enabled` toggles.
+var mdatagenConfigTypes = map[string]bool{"MetricsBuilderConfig": true, "MetricsConfig": true, "ResourceAttributesConfig": true}
+
+// expandMdatagenConfig appends one `enabled` field per metric / resource attribute declared
+// in the metadata.yaml that generated typeExpr. Metric names contain dots, so path tokens
+// are built directly instead of by splitting the key. Returns false when no metadata.yaml is found.
+func expandMdatagenConfig(ctx *packageContext, typeExpr ast.Expr, prefix string, out *[]ConfigField) bool {
+    typeName := typeNameFromExpr(typeExpr)
+    defCtx, st := resolveStructFromExprWithCtx(ctx, typeExpr)
+    if defCtx == nil || st == nil || len(defCtx.files) == 0 { return false }
+    // Only mdatagen output (internal/metadata/generated_config.go); components may declare their own MetricsConfig.
+    // packageContext.dir is the loading directory, so locate the generated package by its files.
+    genDir := ""
+    for _, f := range defCtx.files {
+        if file := defCtx.fset.Position(f.Package).Filename; filepath.Base(file) == "generated_config.go" { genDir = filepath.Dir(file) }
+    }
+    if genDir == "" { return false }
+    md := findMetadataYAML(genDir)
+    if md == nil { return false }
+    base := makePathTokens(prefix)
+    // declaredIn is the generated struct holding the toggle (MetricConfig, ResourceAttributeConfig)
+    toggle := func(tokens []string, declaredIn, description string, enabled bool) {
+        parent := append(append([]string(nil), base...), tokens...)
+        tokens = append(append([]string(nil), parent...), "enabled")
+        *out = append(*out, ConfigField{
+            Name:         "Enabled",
+            Type:         "bool",
+            GoType:       "bool",
+            MapStructure: strings.Join(tokens, "."),
+            Description:  description,
+            Default:      enabled,
+            PathTokens:   tokens,
+            origins:      []fieldOrigin{{pkg: defCtx.path, typ: declaredIn, path: parent}},
+        })
+    }
+    switch typeName {
+    case "MetricsBuilderConfig":
+        for _, m := range md.emittedMetrics() { toggle([]string{"metrics", m.Name}, "MetricConfig", m.Description, m.Enabled) }
+        for _, a := range md.resourceAttributes() { toggle([]string{"resource_attributes", a.Name}, "ResourceAttributeConfig", a.Description, a.Enabled) }
+    case "MetricsConfig":
+        for _, m := range md.emittedMetrics() { toggle([]string{m.Name}, "MetricConfig", m.Description, m.Enabled) }
+    case "ResourceAttributesConfig":
+        for _, a := range md.resourceAttributes() { toggle([]string{a.Name}, "ResourceAttributeConfig", a.Description, a.Enabled) }
+    }
+    return true
+}
+
+// findMetadataYAML looks for metadata.yaml in dir and up to three parents
+// (internal/metadata -> component or scraper directory).
+func findMetadataYAML(dir string) *componentMetadata {
+    for i := 0; i < 4 && dir != "" && dir != "." && dir != string(filepath.Separator); i++ {
+        if fileExists(filepath.Join(dir, "metadata.yaml")) { return readComponentMetadata(dir) }
+        dir = filepath.Dir(dir)
+    }
+    return nil
+}
@@ -9,6 +9,10 @@ ROOT_DIR="$(cd "$SCRIPT_DIR/../.." && pwd)"
 WORK_DIR="$SCRIPT_DIR/../.work"
 COLLECTOR_DIR="$WORK_DIR/opentelemetry-collector"
 CONTRIB_DIR="$WORK_DIR/opentelemetry-collector-contrib"
+# Distribution manifests (which components ship in otelcol, otelcol-contrib, ...); optional
+RELEASES_DIR="$WORK_DIR/opentelemetry-collector-releases"
+# Per-component extraction cache shared across versions (unchanged components are reused)
+CACHE_DIR="$WORK_DIR/extract-cache"
 # Default output directory is at project_root/satellite/Resources
 OUTPUT_DIR="$ROOT_DIR/satellite/Resources"
 
@@ -135,6 +139,13 @@ clone_repos() {
         log "Updating opentelemetry-collector-contrib..."
         cd "$CONTRIB_DIR" && git fetch --all --tags && cd - > /dev/null
     fi
+    if [ ! -d "$RELEASES_DIR" ]; then
+        log "Cloning opentelemetry-collector-releases..."
+        git clone https://github.com/open-telemetry/opentelemetry-collector-releases.git "$RELEASES_DIR" || warn "Failed to clone releases repo; distributions will not be recorded"
+    else
+        log "Updating opentelemetry-collector-releases..."
+        cd "$RELEASES_DIR" && git fetch --all --tags && cd - > /dev/null
+    fi
 }
 
 # Extract configs for a specific version
@@ -171,6 +182,18 @@ extract_version() {
     
     local contrib_commit=$(git rev-parse HEAD)
     log "Contrib at commit: ${contrib_commit:0:8}"
+
+    # Releases manifests for the same tag, when available
+    local releases_args=()
+    if [ -d "$RELEASES_DIR" ]; then
+        cd "$RELEASES_DIR"
+        if git checkout "$version" >/dev/null 2>&1; then
+            releases_args=(--releases-path="$RELEASES_DIR")
+            log "Releases at commit: $(git rev-parse --short=8 HEAD)"
+        else
+            warn "No $version tag in releases repository; distributions will not be recorded"
+        fi
+    fi
     
     cd "$SCRIPT_DIR"
     
@@ -187,13 +210,15 @@ extract_version() {
     fi
     log "Running config extraction (LOCOL_DEBUG=${LOCOL_DEBUG})..."
     if [ "$LOCOL_DEBUG" = "1" ]; then
-        >&2 echo "go run main.go --version=$version --collector-path=$COLLECTOR_DIR --contrib-path=$CONTRIB_DIR --output=$output_file"
+        >&2 echo "go run . --version=$version --collector-path=$COLLECTOR_DIR --contrib-path=$CONTRIB_DIR --output=$output_file --cache-dir=$CACHE_DIR ${releases_args[*]}"
     fi
-    LOCOL_DEBUG="$LOCOL_DEBUG" go run main.go \
+    LOCOL_DEBUG="$LOCOL_DEBUG" go run . \
         --version="$version" \
         --collector-path="$COLLECTOR_DIR" \
         --contrib-path="$CONTRIB_DIR" \
-        --output="$output_file"
+        --output="$output_file" \
+        --cache-dir="$CACHE_DIR" \
+        "${releases_args[@]}"
     
     local extract_result=$?
     
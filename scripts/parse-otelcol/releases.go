@@ -0,0 +1,63 @@
+package main
+
+import (
+    "os"
+    "path/filepath"
+    "strings"
+
+    "gopkg.in/yaml.v3"
+)
+
+// releaseManifest is the part of an opentelemetry-collector-releases
+// distributions/<name>/manifest.yaml (an ocb builder config) used by the extractor.
+type releaseManifest struct {
+    Dist struct {
+        Name string `yaml:"name"`
+    } `yaml:"dist"`
+    Receivers  []releaseModule `yaml:"receivers"`
+    Processors []releaseModule `yaml:"processors"`
+    Exporters  []releaseModule `yaml:"exporters"`
+    Extensions []releaseModule `yaml:"extensions"`
+    Connectors []releaseModule `yaml:"connectors"`
+}
+
+type releaseModule struct {
+    GoMod string `yaml:"gomod"` // "<module> <version>"
+}
+
+// readReleaseManifests maps component module paths to the official distributions
+// (otelcol, otelcol-contrib, otelcol-k8s, otelcol-otlp, ...) whose manifest lists them.
+// It also returns every distribution read, sorted.
+func readReleaseManifests(releasesPath string) (map[string][]string, []string) {
+    paths, _ := filepath.Glob(filepath.Join(releasesPath, "distributions", "*", "manifest.yaml"))
+    byModule := map[string][]string{}
+    var dists []string
+    for _, p := range paths {
+        data, err := os.ReadFile(p)
+        if err != nil { continue }
+        var m releaseManifest
+        if err := yaml.Unmarshal(data, &m); err != nil {
+            dbgf("[extractor] warn: %s: %v\n", p, err)
+            continue
+        }
+        name := m.Dist.Name
+        if name == "" { name = filepath.Base(filepath.Dir(p)) }
+        dists = append(dists, name)
+        for _, list := range [][]releaseModule{m.Receivers, m.Processors, m.Exporters, m.Extensions, m.Connectors} {
+            for _, rm := range list {
+                fields := strings.Fields(rm.GoMod)
+                if len(fields) == 0 { continue }
+                byModule[fields[0]] = append(byModule[fields[0]], name)
+            }
+        }
+    }
+    for mod := range byModule { byModule[mod] = uniqueSorted(byModule[mod]) }
+    return byModule, uniqueSorted(dists)
+}
+
+// applyReleaseDistributions records on each component the distributions shipping its module.
+func applyReleaseDistributions(components []Component, byModule map[string][]string) {
+    for i := range components {
+        components[i].Releases = byModule[components[i].Module]
+    }
+}
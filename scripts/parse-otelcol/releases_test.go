@@ -0,0 +1,15 @@
+package main
+
+import (
+    "encoding/json"
+    "path/filepath"
+    "testing"
+)
+
+// TestReleasesGolden maps fixture modules to the releases manifests shipping them.
+func TestReleasesGolden(t *testing.T) {
+    byModule, dists := readReleaseManifests(filepath.Join("testdata", "releases"))
+    got, err := json.MarshalIndent(map[string]any{"distributions": dists, "modules": byModule}, "", "  ")
+    if err != nil { t.Fatal(err) }
+    checkGolden(t, filepath.Join("testdata", "golden", "releases.json"), append(got, '\n'))
+}
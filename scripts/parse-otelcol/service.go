@@ -0,0 +1,118 @@
+package main
+
+import (
+    "path/filepath"
+    "strings"
+)
+
+// serviceTelemetryDirs are the packages (relative to service/) declaring the telemetry
+// config, newest layout first: otelconftelemetry since service.Config.Telemetry became a
+// factory-created component.Config, the telemetry package before that.
+var serviceTelemetryDirs = []string{"telemetry/otelconftelemetry", "telemetry"}
+
+// pipelineListRefs maps the lists of a service pipeline to the component kind they name.
+var pipelineListRefs = map[string]string{"receivers": "receiver", "processors": "processor", "exporters": "exporter"}
+
+// extractServiceComponent extracts the service:: section of the core repo as a
+// pseudo-component (type and name "service"): service.Config for extensions and
+// pipelines plus the telemetry config with the defaults of its factory.
+func extractServiceComponent(collectorPath string) *Component {
+    dir := filepath.Join(collectorPath, "service")
+    configPath := filepath.Join(dir, "config.go")
+    if !fileExists(configPath) { return nil }
+    schema, err := extractConfigSchemaRecursive(dir, configPath, "Config")
+    if err != nil || schema.StructName == "" {
+        dbgf("[extractor] warn: service config not extracted: %v\n", err)
+        return nil
+    }
+    c := &Component{
+        Name:        "service",
+        Type:        "service",
+        Module:      "go.opentelemetry.io/collector/service",
+        Config:      *schema,
+        Constraints: analyzeConstraints(dir, configPath),
+    }
+    for _, rel := range serviceTelemetryDirs {
+        tdir := filepath.Join(dir, filepath.FromSlash(rel))
+        tel, constraints := extractTelemetrySchema(tdir)
+        if tel == nil { continue }
+        // The telemetry package's own schema (with factory defaults) replaces whatever
+        // service.Config declares for it: a struct in older releases, component.Config later
+        fields := make([]ConfigField, 0, len(c.Config.Fields)+len(tel.Fields))
+        for _, f := range c.Config.Fields {
+            if len(f.PathTokens) == 0 || f.PathTokens[0] != "telemetry" { fields = append(fields, f) }
+        }
+        for _, f := range tel.Fields { fields = append(fields, prefixField(f, "telemetry")) }
+        c.Config.Fields = fields
+        for _, cs := range constraints {
+            for i, k := range cs.KeyTokens { cs.KeyTokens[i] = append([]string{"telemetry"}, k...) }
+            c.Constraints = append(c.Constraints, cs)
+        }
+        break
+    }
+    annotateServiceRefs(c.Config.Fields)
+    for _, f := range c.Config.Fields {
+        if f.CustomUnmarshal { c.CustomUnmarshal = true; break }
+    }
+    if ctx, err := loadPackage(dir, "."); err == nil { c.Description = packageDocSummary(ctx) }
+    return c
+}
+
+// extractTelemetrySchema extracts the telemetry config struct of dir with its factory
+// defaults; nil when dir does not declare one.
+func extractTelemetrySchema(dir string) (*ConfigSchema, []Constraint) {
+    configPath := filepath.Join(dir, "config.go")
+    if !fileExists(configPath) { return nil, nil }
+    fset, factoryAST := parseFactoryFile(filepath.Join(dir, "factory.go"))
+    preferredRoot := ""
+    if factoryAST != nil { preferredRoot = findRootConfigTypeFromFactoryAST(factoryAST) }
+    schema, err := extractConfigSchemaRecursive(dir, configPath, preferredRoot)
+    if err != nil || len(schema.Fields) == 0 { return nil, nil }
+    applyDefaults(schema, extractDefaultsDeepWithAST(dir, configPath, fset, factoryAST))
+    return schema, analyzeConstraints(dir, configPath)
+}
+
+// prefixField nests a field (and the replacement and struct paths it names) under a parent key.
+func prefixField(f ConfigField, parent string) ConfigField {
+    f.MapStructure = parent + "." + f.MapStructure
+    f.PathTokens = append([]string{parent}, f.PathTokens...)
+    if f.Replacement != "" { f.Replacement = parent + "." + f.Replacement }
+    if f.Shared != "" { f.SharedPath = append([]string{parent}, f.SharedPath...) }
+    if f.DeclaredIn != "" { f.DeclaredPath = append([]string{parent}, f.DeclaredPath...) }
+    return f
+}
+
+// annotateServiceRefs marks service.extensions and the pipeline lists as component references.
+func annotateServiceRefs(fields []ConfigField) {
+    for i := range fields {
+        f := &fields[i]
+        tokens := f.PathTokens
+        if n := len(tokens); n > 0 && tokens[n-1] == "[]" { tokens = tokens[:n-1] }
+        kind := ""
+        switch {
+        case len(tokens) == 1 && tokens[0] == "extensions":
+            kind = "extension"
+        case len(tokens) == 3 && tokens[0] == "pipelines" && tokens[1] == "{key}":
+            kind = pipelineListRefs[tokens[2]]
+        }
+        if kind == "" || (f.Type != "array" && f.Type != "custom") { continue }
+        f.Type, f.ItemType, f.RefKind, f.EnumValues = "array", "componentRef", kind, nil
+    }
+}
+
+// applyServiceTelemetry derives the metrics levels of the document schema from the
+// extracted service.telemetry.metrics.level field, whose values keep the declaration
+// (least to most verbose) order, keeping the built-in list otherwise.
+func applyServiceTelemetry(d *DocumentSchema, service *Component) {
+    if service == nil { return }
+    for i := range service.Config.Fields {
+        f := &service.Config.Fields[i]
+        if f.MapStructure != "telemetry.metrics.level" { continue }
+        if len(f.EnumValues) > 0 {
+            levels := make([]string, len(f.EnumValues))
+            for i, v := range f.EnumValues { levels[i] = strings.ToLower(v) }
+            d.Telemetry.MetricsLevels, f.EnumValues = levels, levels
+        }
+        if s, ok := f.Default.(string); ok && s != "" { d.Telemetry.DefaultLevel = strings.ToLower(s) }
+    }
+}
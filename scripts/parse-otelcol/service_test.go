@@ -0,0 +1,54 @@
+package main
+
+import (
+    "encoding/json"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+// TestServiceGolden extracts the fixture service section (pipelines, extensions and the
+// otelconf-based telemetry config) as a pseudo-component.
+func TestServiceGolden(t *testing.T) {
+    c := extractServiceComponent(fixturePackages(t))
+    if c == nil { t.Fatal("service extraction failed") }
+    doc := buildDocumentSchema()
+    applyServiceTelemetry(&doc, c)
+    got, err := json.MarshalIndent(c, "", "  ")
+    if err != nil { t.Fatal(err) }
+    checkGolden(t, filepath.Join("testdata", "golden", "service.json"), append(got, '\n'))
+    if want := []string{"none", "basic", "normal", "detailed"}; strings.Join(doc.Telemetry.MetricsLevels, ",") != strings.Join(want, ",") {
+        t.Errorf("metrics levels = %v, want %v", doc.Telemetry.MetricsLevels, want)
+    }
+}
+
+// TestValidateService checks the service section against the extracted service schema,
+// next to the pipeline checks.
+func TestValidateService(t *testing.T) {
+    data, err := loadExtractedData(schemaFixture)
+    if err != nil { t.Fatal(err) }
+    data.Service = extractServiceComponent(fixturePackages(t))
+    config := `receivers:
+  otlp:
+exporters:
+  otlp:
+    endpoint: backend:4317
+    token: ${env:TOKEN}
+service:
+  telemetry:
+    metrics:
+      level: verbose
+    traces: {}
+  bogus: true
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [otlp]
+`
+    issues, err := validateConfig(newSchemaIndex(data), "collector.yaml", []byte(config), validateOptions{})
+    if err != nil { t.Fatal(err) }
+    checkIssues(t, issues,
+        `collector.yaml:10:14: error: service: telemetry.metrics.level: invalid value "verbose"; expected one of: none, basic, normal, detailed`,
+        "collector.yaml:12:3: error: service: bogus: unknown key",
+    )
+}
@@ -0,0 +1,13 @@
+package confighttp
+
+import "time"
+
+// ClientConfig configures an HTTP client.
+type ClientConfig struct {
+    // Endpoint is the target URL.
+    Endpoint string `mapstructure:"endpoint"`
+    // Timeout for requests.
+    Timeout time.Duration `mapstructure:"timeout"`
+    // Headers added to every request.
+    Headers map[string]string `mapstructure:"headers"`
+}
@@ -0,0 +1,13 @@
+package confighttp
+
+import "go.opentelemetry.io/collector/featuregate"
+
+const strictHeadersGateID = "confighttp.strictHeaders"
+
+// StrictHeadersGate rejects requests with malformed headers.
+var StrictHeadersGate = featuregate.GlobalRegistry().MustRegister(
+    strictHeadersGateID,
+    featuregate.StageAlpha,
+    featuregate.WithRegisterDescription("Reject requests with malformed headers."),
+    featuregate.WithRegisterFromVersion("v0.120.0"),
+)
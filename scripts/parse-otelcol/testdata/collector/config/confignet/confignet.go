@@ -0,0 +1,18 @@
+package confignet
+
+// TransportType is the network transport.
+type TransportType string
+
+const (
+    TransportTypeTCP  TransportType = "tcp"
+    TransportTypeUDP  TransportType = "udp"
+    TransportTypeUnix TransportType = "unix"
+)
+
+// AddrConfig is a network address.
+type AddrConfig struct {
+    // Endpoint is the address to listen on.
+    Endpoint string `mapstructure:"endpoint"`
+    // Transport to use.
+    Transport TransportType `mapstructure:"transport"`
+}
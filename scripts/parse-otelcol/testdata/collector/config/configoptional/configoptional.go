@@ -0,0 +1,10 @@
+package configoptional
+
+// Optional holds a value that may be unset.
+type Optional[T any] struct {
+    value    T
+    hasValue bool
+}
+
+// Some returns an Optional holding v.
+func Some[T any](v T) Optional[T] { return Optional[T]{value: v, hasValue: true} }
@@ -0,0 +1,22 @@
+package configretry
+
+import "time"
+
+// BackOffConfig defines how failed requests are retried.
+type BackOffConfig struct {
+    // Enabled indicates whether to retry failed requests.
+    Enabled bool `mapstructure:"enabled"`
+    // InitialInterval is the time to wait after the first failure.
+    InitialInterval time.Duration `mapstructure:"initial_interval"`
+    // MaxInterval caps the wait between consecutive retries.
+    MaxInterval time.Duration `mapstructure:"max_interval"`
+}
+
+// NewDefaultBackOffConfig returns the default retry settings.
+func NewDefaultBackOffConfig() BackOffConfig {
+    return BackOffConfig{
+        Enabled:         true,
+        InitialInterval: 5 * time.Second,
+        MaxInterval:     30 * time.Second,
+    }
+}
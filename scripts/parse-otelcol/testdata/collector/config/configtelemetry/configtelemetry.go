@@ -0,0 +1,62 @@
+package configtelemetry
+
+import (
+    "errors"
+    "strings"
+)
+
+// Level is the level of internal telemetry (metrics, logs, traces about the component itself)
+// that every component should generate.
+type Level int32
+
+const (
+    // LevelNone indicates that no telemetry data should be collected.
+    LevelNone Level = iota
+    // LevelBasic is the recommended and covers the basics of the service telemetry.
+    LevelBasic
+    // LevelNormal adds some other indicators on top of basic.
+    LevelNormal
+    // LevelDetailed adds dimensions and views to the previous levels.
+    LevelDetailed
+)
+
+const (
+    levelNoneStr     = "None"
+    levelBasicStr    = "Basic"
+    levelNormalStr   = "Normal"
+    levelDetailedStr = "Detailed"
+)
+
+func (l Level) String() string {
+    switch l {
+    case LevelNone:
+        return levelNoneStr
+    case LevelBasic:
+        return levelBasicStr
+    case LevelNormal:
+        return levelNormalStr
+    case LevelDetailed:
+        return levelDetailedStr
+    }
+    return ""
+}
+
+// UnmarshalText unmarshalls text to a Level.
+func (l *Level) UnmarshalText(text []byte) error {
+    if l == nil {
+        return errors.New("cannot unmarshal to a nil *Level")
+    }
+    switch strings.ToLower(string(text)) {
+    case strings.ToLower(levelNoneStr):
+        *l = LevelNone
+    case strings.ToLower(levelBasicStr):
+        *l = LevelBasic
+    case strings.ToLower(levelNormalStr):
+        *l = LevelNormal
+    case strings.ToLower(levelDetailedStr):
+        *l = LevelDetailed
+    default:
+        return errors.New("unknown metrics level")
+    }
+    return nil
+}
@@ -0,0 +1,7 @@
+package pairconnector
+
+// Config for the pair connector.
+type Config struct {
+    // Dimensions added to generated metrics.
+    Dimensions []string `mapstructure:"dimensions"`
+}
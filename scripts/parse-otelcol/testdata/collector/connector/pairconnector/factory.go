@@ -0,0 +1,14 @@
+package pairconnector
+
+import (
+    "go.opentelemetry.io/collector/component"
+    "go.opentelemetry.io/collector/connector"
+)
+
+func NewFactory() connector.Factory {
+    return connector.NewFactory(component.MustNewType("pair"), createDefaultConfig,
+        connector.WithTracesToMetrics(createTracesToMetrics, component.StabilityLevelAlpha),
+        connector.WithLogsToMetrics(createLogsToMetrics, component.StabilityLevelDevelopment))
+}
+
+func createDefaultConfig() component.Config { return &Config{} }
@@ -0,0 +1,14 @@
+package aliasexporter
+
+import "go.opentelemetry.io/collector/config/confighttp"
+
+// ClientConfig is an alias to the shared HTTP client settings.
+type ClientConfig = confighttp.ClientConfig
+
+// Config for the alias exporter.
+type Config struct {
+    // Client settings for the backend.
+    Client ClientConfig `mapstructure:"client"`
+    // Compression codec.
+    Compression string `mapstructure:"compression"`
+}
@@ -0,0 +1,7 @@
+package aliasexporter
+
+import "go.opentelemetry.io/collector/config/confighttp"
+
+func strictHeaders() bool {
+    return confighttp.StrictHeadersGate.IsEnabled()
+}
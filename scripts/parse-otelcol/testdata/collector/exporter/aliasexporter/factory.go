@@ -0,0 +1,20 @@
+package aliasexporter
+
+import (
+    "time"
+
+    "go.opentelemetry.io/collector/component"
+    "go.opentelemetry.io/collector/exporter"
+)
+
+func NewFactory() exporter.Factory {
+    return exporter.NewFactory(component.MustNewType("alias"), createDefaultConfig,
+        exporter.WithLogs(createLogs, component.StabilityLevelAlpha))
+}
+
+func createDefaultConfig() component.Config {
+    return &Config{
+        Client:      ClientConfig{Timeout: 30 * time.Second},
+        Compression: "gzip",
+    }
+}
@@ -0,0 +1,37 @@
+package constraintexporter
+
+import "errors"
+
+// Config for the constraint exporter.
+type Config struct {
+    // Endpoint of the backend.
+    Endpoint string `mapstructure:"endpoint"`
+    // URL of the backend; alternative to endpoint.
+    URL string `mapstructure:"url"`
+    // Token authenticates requests.
+    Token string `mapstructure:"token"`
+    // APIKey authenticates requests.
+    APIKey string `mapstructure:"api_key"`
+    // Insecure disables TLS.
+    Insecure bool `mapstructure:"insecure"`
+    // CAFile is the CA certificate.
+    CAFile string `mapstructure:"ca_file"`
+    // ClientKey is the client private key, a file path or inline PEM.
+    ClientKey string `mapstructure:"client_key"`
+}
+
+func (cfg *Config) Validate() error {
+    if cfg.Endpoint == "" && cfg.URL == "" {
+        return errors.New("one of endpoint or url must be set")
+    }
+    if cfg.Token == "" && cfg.APIKey == "" {
+        return errors.New("either token or api_key is required")
+    }
+    if cfg.Token != "" && cfg.APIKey != "" {
+        return errors.New("token and api_key are mutually exclusive")
+    }
+    if cfg.Insecure && cfg.CAFile != "" {
+        return errors.New("ca_file cannot be used with insecure")
+    }
+    return nil
+}
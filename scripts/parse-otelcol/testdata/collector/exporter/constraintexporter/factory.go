@@ -0,0 +1,13 @@
+package constraintexporter
+
+import (
+    "go.opentelemetry.io/collector/component"
+    "go.opentelemetry.io/collector/exporter"
+)
+
+func NewFactory() exporter.Factory {
+    return exporter.NewFactory(component.MustNewType("constraint"), createDefaultConfig,
+        exporter.WithTraces(createTraces, component.StabilityLevelBeta))
+}
+
+func createDefaultConfig() component.Config { return &Config{} }
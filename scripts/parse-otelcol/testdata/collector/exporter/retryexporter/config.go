@@ -0,0 +1,15 @@
+package retryexporter
+
+import (
+    "go.opentelemetry.io/collector/config/confighttp"
+    "go.opentelemetry.io/collector/config/configretry"
+)
+
+// Config for the retry exporter.
+type Config struct {
+    confighttp.ClientConfig `mapstructure:",squash"`
+    // BackOffConfig configures retries of failed exports.
+    BackOffConfig configretry.BackOffConfig `mapstructure:"retry_on_failure"`
+    // Reconnect configures retries of failed connection attempts.
+    Reconnect configretry.BackOffConfig `mapstructure:"reconnect"`
+}
@@ -0,0 +1,23 @@
+package retryexporter
+
+import (
+    "time"
+
+    "go.opentelemetry.io/collector/component"
+    "go.opentelemetry.io/collector/config/confighttp"
+    "go.opentelemetry.io/collector/config/configretry"
+    "go.opentelemetry.io/collector/exporter"
+)
+
+func NewFactory() exporter.Factory {
+    return exporter.NewFactory(component.MustNewType("retry"), createDefaultConfig,
+        exporter.WithTraces(createTraces, component.StabilityLevelBeta))
+}
+
+func createDefaultConfig() component.Config {
+    return &Config{
+        ClientConfig:  confighttp.ClientConfig{Timeout: 10 * time.Second},
+        BackOffConfig: configretry.NewDefaultBackOffConfig(),
+        Reconnect:     configretry.BackOffConfig{Enabled: true, InitialInterval: time.Second, MaxInterval: 30 * time.Second},
+    }
+}
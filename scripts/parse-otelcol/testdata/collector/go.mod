@@ -0,0 +1,10 @@
+// Miniature collector tree used by the extractor golden tests. Packages such as
+// component, receiver and exporter are intentionally absent: the extractor only
+// parses syntax, so the fixtures never need to build.
+module go.opentelemetry.io/collector
+
+go 1.22
+
+require go.opentelemetry.io/contrib/otelconf v0.3.0
+
+replace go.opentelemetry.io/contrib/otelconf => ./third_party/otelconf
@@ -0,0 +1,92 @@
+package enumprocessor
+
+import (
+    "fmt"
+    "strings"
+)
+
+// Mode selects the processing strategy.
+type Mode string
+
+const (
+    ModeFast     Mode = "fast"
+    ModeSafe     Mode = "safe"
+    ModeBalanced Mode = "balanced"
+)
+
+// Verbosity is parsed from its name (quiet, normal, loud).
+type Verbosity int
+
+const (
+    VerbosityQuiet Verbosity = iota
+    VerbosityNormal
+    VerbosityLoud
+)
+
+// UnmarshalText parses a Verbosity name.
+func (v *Verbosity) UnmarshalText(text []byte) error {
+    switch strings.ToLower(string(text)) {
+    case "quiet":
+        *v = VerbosityQuiet
+    case "normal":
+        *v = VerbosityNormal
+    case "loud":
+        *v = VerbosityLoud
+    default:
+        return fmt.Errorf("unknown verbosity %q", text)
+    }
+    return nil
+}
+
+// Severity names put the type name last, like zapcore.Level (debug, info, error).
+type Severity int8
+
+const (
+    DebugSeverity Severity = iota - 1
+    InfoSeverity
+    ErrorSeverity
+)
+
+// UnmarshalText parses a Severity name.
+func (s *Severity) UnmarshalText(text []byte) error {
+    switch strings.ToLower(string(text)) {
+    case "debug":
+        *s = DebugSeverity
+    case "info":
+        *s = InfoSeverity
+    case "error":
+        *s = ErrorSeverity
+    default:
+        return fmt.Errorf("unknown severity %q", text)
+    }
+    return nil
+}
+
+// Priority is decoded from its integer value only.
+type Priority int
+
+const (
+    PriorityLow Priority = iota
+    PriorityHigh
+)
+
+// LevelThreshold is a ratio, not an enum, despite its name.
+type LevelThreshold float64
+
+// Config for the enum processor.
+type Config struct {
+    // Mode of operation.
+    Mode Mode `mapstructure:"mode"`
+    // Level is one of "low", "medium" or "high".
+    Level string `mapstructure:"level"`
+    // Verbosity of the processor logs.
+    Verbosity Verbosity `mapstructure:"verbosity"`
+    // MinSeverity of the items to keep.
+    MinSeverity Severity `mapstructure:"min_severity"`
+    // Priority of the processed items.
+    Priority Priority `mapstructure:"priority"`
+    // Threshold above which items are dropped.
+    Threshold LevelThreshold `mapstructure:"threshold"`
+    // Modes lists additional modes.
+    Modes []Mode `mapstructure:"modes"`
+}
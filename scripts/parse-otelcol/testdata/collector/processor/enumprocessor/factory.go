@@ -0,0 +1,15 @@
+package enumprocessor
+
+import (
+    "go.opentelemetry.io/collector/component"
+    "go.opentelemetry.io/collector/processor"
+)
+
+func NewFactory() processor.Factory {
+    return processor.NewFactory(component.MustNewType("enum"), createDefaultConfig,
+        processor.WithTraces(createTraces, component.StabilityLevelAlpha))
+}
+
+func createDefaultConfig() component.Config {
+    return &Config{Mode: ModeSafe}
+}
@@ -0,0 +1,21 @@
+package mutateprocessor
+
+import "time"
+
+// QueueConfig configures the in-memory queue.
+type QueueConfig struct {
+    // Enabled turns the queue on.
+    Enabled bool `mapstructure:"enabled"`
+    // Size is the queue capacity.
+    Size int `mapstructure:"size"`
+}
+
+// Config for the mutate processor.
+type Config struct {
+    // Timeout per batch.
+    Timeout time.Duration `mapstructure:"timeout"`
+    // Retries before giving up.
+    Retries int `mapstructure:"retries"`
+    // Queue settings.
+    Queue QueueConfig `mapstructure:"queue"`
+}
@@ -0,0 +1,23 @@
+package mutateprocessor
+
+import (
+    "time"
+
+    "go.opentelemetry.io/collector/component"
+    "go.opentelemetry.io/collector/processor"
+)
+
+func NewFactory() processor.Factory {
+    return processor.NewFactory(component.MustNewType("mutate"), createDefaultConfig,
+        processor.WithMetrics(createMetrics, component.StabilityLevelBeta))
+}
+
+func createDefaultConfig() component.Config {
+    queue := QueueConfig{Size: 1000}
+    queue.Enabled = true
+    return &Config{
+        Timeout: 5 * time.Second,
+        Retries: 3,
+        Queue:   queue,
+    }
+}
@@ -0,0 +1,83 @@
+package routeprocessor
+
+import "errors"
+
+// Route sends matching telemetry to a set of pipelines.
+type Route struct {
+    // Statement is an OTTL condition selecting the telemetry.
+    Statement string `mapstructure:"statement" validate:"required"`
+    // Pipelines receive the matching telemetry.
+    Pipelines []PipelineID `mapstructure:"pipelines"`
+}
+
+// Routes is the routing table.
+type Routes []Route
+
+// PipelineID unmarshals from a "<signal>[/<name>]" string.
+type PipelineID struct {
+    signal string
+    name   string
+}
+
+// ContextStatements groups OTTL statements evaluated in one context.
+type ContextStatements struct {
+    // Context is the OTTL context, e.g. "resource" or "log".
+    Context string `mapstructure:"context"`
+    // Statements to execute.
+    Statements []string `mapstructure:"statements"`
+}
+
+// Matcher matches telemetry by attributes.
+type Matcher struct {
+    // Attributes that must all match.
+    Attributes []Attribute `mapstructure:"attributes"`
+}
+
+// Attribute is a key/value pair to match.
+type Attribute struct {
+    // Key of the attribute.
+    Key string `mapstructure:"key" validate:"required"`
+    // Value of the attribute.
+    Value string `mapstructure:"value"`
+}
+
+// RetryConfig controls retries of failed routes.
+type RetryConfig struct {
+    // Enabled turns retries on.
+    Enabled bool `mapstructure:"enabled"`
+    // MaxAttempts per item.
+    MaxAttempts int `mapstructure:"max_attempts"`
+}
+
+// Config for the route processor.
+type Config struct {
+    // Table of routes evaluated in order.
+    Table Routes `mapstructure:"table"`
+    // LogStatements are applied before routing.
+    LogStatements []ContextStatements `mapstructure:"log_statements"`
+    // Matchers by name.
+    Matchers map[string]*Matcher `mapstructure:"matchers"`
+    // DefaultPipelines receive unmatched telemetry.
+    DefaultPipelines []PipelineID `mapstructure:"default_pipelines"`
+    // Default is the name of the fallback pipeline.
+    // Deprecated: [v0.110.0] Use DefaultPipelines instead.
+    Default string `mapstructure:"default"`
+    // DefaultPipeline is the legacy fallback pipeline.
+    DefaultPipeline string `mapstructure:"default_pipeline"`
+    // Fallback is used while the deprecated `default` setting is unset; use
+    // `default_pipelines` for new configs.
+    Fallback string `mapstructure:"fallback"`
+    // Retry of failed routes. See the README for the deprecation policy of its settings.
+    Retry RetryConfig `mapstructure:"retry"`
+    // LegacyRetry is the retry section of older releases.
+    //
+    // Deprecated: use `retry` instead.
+    LegacyRetry RetryConfig `mapstructure:"legacy_retry"`
+}
+
+func (cfg *Config) Validate() error {
+    if cfg.DefaultPipeline != "" {
+        return errors.New("default_pipeline is deprecated; use `default_pipelines` instead")
+    }
+    return nil
+}
@@ -0,0 +1,15 @@
+package routeprocessor
+
+import (
+    "go.opentelemetry.io/collector/component"
+    "go.opentelemetry.io/collector/processor"
+)
+
+func NewFactory() processor.Factory {
+    return processor.NewFactory(component.MustNewType("route"), createDefaultConfig,
+        processor.WithLogs(createLogs, component.StabilityLevelDevelopment))
+}
+
+func createDefaultConfig() component.Config {
+    return &Config{}
+}
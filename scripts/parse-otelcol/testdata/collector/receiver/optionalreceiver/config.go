@@ -0,0 +1,32 @@
+package optionalreceiver
+
+import (
+    "go.opentelemetry.io/collector/config/configoptional"
+)
+
+// GRPCConfig configures the gRPC server.
+type GRPCConfig struct {
+    // Endpoint to listen on.
+    Endpoint string `mapstructure:"endpoint"`
+    // MaxRecvMsgSizeMiB limits the size of received messages.
+    MaxRecvMsgSizeMiB int `mapstructure:"max_recv_msg_size_mib"`
+}
+
+// HTTPConfig configures the HTTP server.
+type HTTPConfig struct {
+    // Endpoint to listen on.
+    Endpoint string `mapstructure:"endpoint"`
+    // TracesURLPath is the URL path for traces.
+    TracesURLPath string `mapstructure:"traces_url_path"`
+}
+
+// Protocols lists the enabled protocols.
+type Protocols struct {
+    GRPC configoptional.Optional[GRPCConfig] `mapstructure:"grpc"`
+    HTTP configoptional.Optional[HTTPConfig] `mapstructure:"http"`
+}
+
+// Config for the optional receiver.
+type Config struct {
+    Protocols `mapstructure:"protocols"`
+}
@@ -0,0 +1,22 @@
+package optionalreceiver
+
+import (
+    "go.opentelemetry.io/collector/component"
+    "go.opentelemetry.io/collector/config/configoptional"
+    "go.opentelemetry.io/collector/receiver"
+)
+
+func NewFactory() receiver.Factory {
+    return receiver.NewFactory(component.MustNewType("optional"), createDefaultConfig,
+        receiver.WithTraces(createTraces, component.StabilityLevelStable),
+        receiver.WithMetrics(createMetrics, component.StabilityLevelStable))
+}
+
+func createDefaultConfig() component.Config {
+    return &Config{
+        Protocols: Protocols{
+            GRPC: configoptional.Some(GRPCConfig{Endpoint: "localhost:4317", MaxRecvMsgSizeMiB: 4}),
+            HTTP: configoptional.Some(HTTPConfig{Endpoint: "localhost:4318", TracesURLPath: "/v1/traces"}),
+        },
+    }
+}
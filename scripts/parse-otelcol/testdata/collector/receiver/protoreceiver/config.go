@@ -0,0 +1,61 @@
+package protoreceiver
+
+import (
+    "errors"
+    "strings"
+
+    "go.opentelemetry.io/collector/confmap"
+)
+
+const (
+    protoGRPC = "protocols::grpc"
+    protoHTTP = "protocols::http"
+)
+
+// Protocols lists the enabled server protocols.
+type Protocols struct {
+    GRPC *ServerConfig `mapstructure:"grpc"`
+    HTTP *ServerConfig `mapstructure:"http"`
+}
+
+// ServerConfig is a protocol server.
+type ServerConfig struct {
+    // Endpoint to listen on.
+    Endpoint string `mapstructure:"endpoint"`
+    // Compression of responses; also accepts a bare algorithm name.
+    Compression Compression `mapstructure:"compression"`
+}
+
+// Compression accepts either {algorithm, level} or a string such as "gzip".
+type Compression struct {
+    Algorithm string `mapstructure:"algorithm"`
+    Level     int    `mapstructure:"level"`
+}
+
+func (c *Compression) UnmarshalText(text []byte) error {
+    c.Algorithm = strings.TrimSpace(string(text))
+    return nil
+}
+
+// Config for the proto receiver.
+type Config struct {
+    // Protocols to serve.
+    Protocols Protocols `mapstructure:"protocols"`
+}
+
+// Unmarshal drops protocols that are not configured explicitly.
+func (cfg *Config) Unmarshal(conf *confmap.Conf) error {
+    if err := conf.Unmarshal(cfg); err != nil {
+        return err
+    }
+    if !conf.IsSet(protoGRPC) {
+        cfg.Protocols.GRPC = nil
+    }
+    if !conf.IsSet(protoHTTP) {
+        cfg.Protocols.HTTP = nil
+    }
+    if conf.IsSet("legacy_endpoint") {
+        return errors.New("legacy_endpoint is deprecated; use `protocols::grpc::endpoint` instead")
+    }
+    return nil
+}
@@ -0,0 +1,15 @@
+package protoreceiver
+
+import (
+    "go.opentelemetry.io/collector/component"
+    "go.opentelemetry.io/collector/receiver"
+)
+
+func NewFactory() receiver.Factory {
+    return receiver.NewFactory(component.MustNewType("proto"), createDefaultConfig,
+        receiver.WithTraces(createTraces, component.StabilityLevelBeta))
+}
+
+func createDefaultConfig() component.Config {
+    return &Config{}
+}
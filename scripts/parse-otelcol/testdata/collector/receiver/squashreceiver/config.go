@@ -0,0 +1,21 @@
+package squashreceiver
+
+import (
+    "go.opentelemetry.io/collector/config/confignet"
+    "go.opentelemetry.io/collector/receiver/squashreceiver/internal/metadata"
+)
+
+// Common settings shared with other receivers.
+type Common struct {
+    // Name of the instance.
+    Name string `mapstructure:"name"`
+}
+
+// Config for the squash receiver.
+type Config struct {
+    confignet.AddrConfig          `mapstructure:",squash"`
+    Common                        `mapstructure:",squash"`
+    metadata.MetricsBuilderConfig `mapstructure:",squash"`
+    // Verbose enables debug logging.
+    Verbose bool `mapstructure:"verbose"`
+}
@@ -0,0 +1,3 @@
+// Package squashreceiver exercises squash embedding of local, external and
+// mdatagen-generated structs.
+package squashreceiver
@@ -0,0 +1,24 @@
+package squashreceiver
+
+import (
+    "go.opentelemetry.io/collector/component"
+    "go.opentelemetry.io/collector/config/confignet"
+    "go.opentelemetry.io/collector/receiver"
+    "go.opentelemetry.io/collector/receiver/squashreceiver/internal/metadata"
+)
+
+func NewFactory() receiver.Factory {
+    return receiver.NewFactory(metadata.Type, createDefaultConfig,
+        receiver.WithMetrics(createMetrics, metadata.MetricsStability),
+        receiver.WithLogs(createLogs, component.StabilityLevelDevelopment))
+}
+
+func createDefaultConfig() component.Config {
+    return &Config{
+        AddrConfig: confignet.AddrConfig{
+            Endpoint:  "localhost:4317",
+            Transport: confignet.TransportTypeTCP,
+        },
+        MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+    }
+}
@@ -0,0 +1,37 @@
+package metadata
+
+// MetricConfig provides common config for a particular metric.
+type MetricConfig struct {
+    Enabled bool `mapstructure:"enabled"`
+
+    enabledSetByUser bool
+}
+
+// MetricsConfig provides config for squash metrics.
+type MetricsConfig struct {
+    SquashBytes  MetricConfig `mapstructure:"squash.bytes"`
+    SquashErrors MetricConfig `mapstructure:"squash.errors"`
+}
+
+// ResourceAttributeConfig provides common config for a particular resource attribute.
+type ResourceAttributeConfig struct {
+    Enabled bool `mapstructure:"enabled"`
+}
+
+// ResourceAttributesConfig provides config for squash resource attributes.
+type ResourceAttributesConfig struct {
+    HostName ResourceAttributeConfig `mapstructure:"host.name"`
+}
+
+// MetricsBuilderConfig is a configuration for squash metrics builder.
+type MetricsBuilderConfig struct {
+    Metrics            MetricsConfig            `mapstructure:"metrics"`
+    ResourceAttributes ResourceAttributesConfig `mapstructure:"resource_attributes"`
+}
+
+func DefaultMetricsBuilderConfig() MetricsBuilderConfig {
+    return MetricsBuilderConfig{
+        Metrics:            MetricsConfig{SquashBytes: MetricConfig{Enabled: true}},
+        ResourceAttributes: ResourceAttributesConfig{HostName: ResourceAttributeConfig{Enabled: true}},
+    }
+}
@@ -0,0 +1,10 @@
+package metadata
+
+import "go.opentelemetry.io/collector/component"
+
+var Type = component.MustNewType("squash")
+
+const (
+    MetricsStability = component.StabilityLevelBeta
+    LogsStability    = component.StabilityLevelDevelopment
+)
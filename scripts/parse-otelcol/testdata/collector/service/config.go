@@ -0,0 +1,29 @@
+// Package service wires the configured components into pipelines and runs them.
+package service
+
+import (
+    "errors"
+
+    "go.opentelemetry.io/collector/component"
+    "go.opentelemetry.io/collector/service/extensions"
+    "go.opentelemetry.io/collector/service/pipelines"
+)
+
+var errMissingServicePipelines = errors.New("service must have at least one pipeline")
+
+// Config defines the configurable components of the Service.
+type Config struct {
+    // Telemetry is the configuration for collector's own telemetry.
+    Telemetry component.Config `mapstructure:"telemetry"`
+    // Extensions are the ordered list of extensions configured for the service.
+    Extensions extensions.Config `mapstructure:"extensions,omitempty"`
+    // Pipelines are the set of data pipelines configured for the service.
+    Pipelines pipelines.Config `mapstructure:"pipelines"`
+}
+
+func (cfg *Config) Validate() error {
+    if len(cfg.Pipelines) == 0 {
+        return errMissingServicePipelines
+    }
+    return nil
+}
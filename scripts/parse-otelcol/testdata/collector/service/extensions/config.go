@@ -0,0 +1,6 @@
+package extensions
+
+import "go.opentelemetry.io/collector/component"
+
+// Config represents the ordered list of extensions configured for the service.
+type Config []component.ID
@@ -0,0 +1,19 @@
+package pipelines
+
+import (
+    "go.opentelemetry.io/collector/component"
+    "go.opentelemetry.io/collector/pipeline"
+)
+
+// Config defines the configurable settings for service telemetry.
+type Config map[pipeline.ID]*PipelineConfig
+
+// PipelineConfig defines the configuration of a Pipeline.
+type PipelineConfig struct {
+    // Receivers feeding the pipeline.
+    Receivers []component.ID `mapstructure:"receivers"`
+    // Processors applied in order.
+    Processors []component.ID `mapstructure:"processors"`
+    // Exporters receiving the processed data.
+    Exporters []component.ID `mapstructure:"exporters"`
+}
@@ -0,0 +1,52 @@
+package otelconftelemetry
+
+import (
+    config "go.opentelemetry.io/contrib/otelconf/v0.3.0"
+    "go.uber.org/zap/zapcore"
+
+    "go.opentelemetry.io/collector/config/configtelemetry"
+)
+
+// Config defines the configurable settings for service telemetry.
+type Config struct {
+    Logs    LogsConfig    `mapstructure:"logs"`
+    Metrics MetricsConfig `mapstructure:"metrics"`
+    Traces  TracesConfig  `mapstructure:"traces"`
+    // Resource specifies user-defined attributes to include with all emitted telemetry.
+    Resource map[string]*string `mapstructure:"resource"`
+}
+
+// LogsConfig defines the configurable settings for service telemetry logs.
+type LogsConfig struct {
+    // Level is the minimum enabled logging level.
+    Level zapcore.Level `mapstructure:"level"`
+    // Encoding sets the logger's encoding. Valid values are "json" and "console".
+    Encoding string `mapstructure:"encoding"`
+    // OutputPaths is a list of URLs or file paths to write logging output to.
+    OutputPaths []string `mapstructure:"output_paths"`
+    // Processors allow configuration of log record processors to emit logs to any number of supported backends.
+    Processors []config.LogRecordProcessor `mapstructure:"processors,omitempty"`
+}
+
+// MetricsConfig exposes the common Telemetry configuration for one component.
+type MetricsConfig struct {
+    // Level is the level of telemetry metrics, the possible values are:
+    //  - "none" indicates that no telemetry data should be collected;
+    //  - "basic" is the recommended and covers the basics of the service telemetry.
+    //  - "normal" adds some other indicators on top of basic.
+    //  - "detailed" adds dimensions and views to the previous levels.
+    Level configtelemetry.Level `mapstructure:"level"`
+
+    // Readers allow configuration of metric readers to emit metrics to any number of supported backends.
+    config.MeterProvider `mapstructure:",squash"`
+}
+
+// TracesConfig exposes the common Telemetry configuration for collector's internal spans.
+type TracesConfig struct {
+    // Level configures whether spans are emitted or not.
+    Level configtelemetry.Level `mapstructure:"level"`
+    // Propagators is a list of TextMapPropagators from the supported propagators list.
+    Propagators []string `mapstructure:"propagators"`
+    // Processors allow configuration of span processors to emit spans to any number of supported backends.
+    config.TracerProvider `mapstructure:",squash"`
+}
@@ -0,0 +1,25 @@
+package otelconftelemetry
+
+import (
+    "go.uber.org/zap/zapcore"
+
+    "go.opentelemetry.io/collector/component"
+    "go.opentelemetry.io/collector/config/configtelemetry"
+)
+
+func createDefaultConfig() component.Config {
+    return &Config{
+        Logs: LogsConfig{
+            Encoding:    "console",
+            OutputPaths: []string{"stderr"},
+        },
+        Metrics: MetricsConfig{
+            Level: configtelemetry.LevelNormal,
+        },
+        Traces: TracesConfig{
+            Level: configtelemetry.LevelBasic,
+        },
+    }
+}
+
+var _ = zapcore.InfoLevel
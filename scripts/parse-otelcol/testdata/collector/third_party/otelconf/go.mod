@@ -0,0 +1,5 @@
+// Stand-in for go.opentelemetry.io/contrib/otelconf (replaced in ../../go.mod) with the
+// generated types the service telemetry config embeds.
+module go.opentelemetry.io/contrib/otelconf
+
+go 1.22
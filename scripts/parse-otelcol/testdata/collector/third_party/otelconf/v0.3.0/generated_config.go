@@ -0,0 +1,95 @@
+package otelconf
+
+type Console map[string]interface{}
+
+type MeterProvider struct {
+    // Readers corresponds to the JSON schema field "readers".
+    Readers []MetricReader `json:"readers" yaml:"readers" mapstructure:"readers"`
+}
+
+type MetricReader struct {
+    // Periodic corresponds to the JSON schema field "periodic".
+    Periodic *PeriodicMetricReader `json:"periodic,omitempty" yaml:"periodic,omitempty" mapstructure:"periodic,omitempty"`
+    // Pull corresponds to the JSON schema field "pull".
+    Pull *PullMetricReader `json:"pull,omitempty" yaml:"pull,omitempty" mapstructure:"pull,omitempty"`
+}
+
+type PeriodicMetricReader struct {
+    // Exporter corresponds to the JSON schema field "exporter".
+    Exporter PushMetricExporter `json:"exporter" yaml:"exporter" mapstructure:"exporter"`
+    // Interval corresponds to the JSON schema field "interval".
+    Interval *int `json:"interval,omitempty" yaml:"interval,omitempty" mapstructure:"interval,omitempty"`
+}
+
+type PushMetricExporter struct {
+    // Console corresponds to the JSON schema field "console".
+    Console Console `json:"console,omitempty" yaml:"console,omitempty" mapstructure:"console,omitempty"`
+    // OTLP corresponds to the JSON schema field "otlp".
+    OTLP *OTLPMetric `json:"otlp,omitempty" yaml:"otlp,omitempty" mapstructure:"otlp,omitempty"`
+}
+
+type OTLPMetric struct {
+    // Endpoint corresponds to the JSON schema field "endpoint".
+    Endpoint *string `json:"endpoint,omitempty" yaml:"endpoint,omitempty" mapstructure:"endpoint,omitempty"`
+    // Protocol corresponds to the JSON schema field "protocol".
+    Protocol *string `json:"protocol,omitempty" yaml:"protocol,omitempty" mapstructure:"protocol,omitempty"`
+}
+
+type PullMetricReader struct {
+    // Exporter corresponds to the JSON schema field "exporter".
+    Exporter PullMetricExporter `json:"exporter" yaml:"exporter" mapstructure:"exporter"`
+}
+
+type PullMetricExporter struct {
+    // Prometheus corresponds to the JSON schema field "prometheus".
+    Prometheus *Prometheus `json:"prometheus,omitempty" yaml:"prometheus,omitempty" mapstructure:"prometheus,omitempty"`
+}
+
+type Prometheus struct {
+    // Host corresponds to the JSON schema field "host".
+    Host *string `json:"host,omitempty" yaml:"host,omitempty" mapstructure:"host,omitempty"`
+    // Port corresponds to the JSON schema field "port".
+    Port *int `json:"port,omitempty" yaml:"port,omitempty" mapstructure:"port,omitempty"`
+}
+
+type TracerProvider struct {
+    // Processors corresponds to the JSON schema field "processors".
+    Processors []SpanProcessor `json:"processors" yaml:"processors" mapstructure:"processors"`
+}
+
+type SpanProcessor struct {
+    // Batch corresponds to the JSON schema field "batch".
+    Batch *BatchSpanProcessor `json:"batch,omitempty" yaml:"batch,omitempty" mapstructure:"batch,omitempty"`
+}
+
+type BatchSpanProcessor struct {
+    // Exporter corresponds to the JSON schema field "exporter".
+    Exporter SpanExporter `json:"exporter" yaml:"exporter" mapstructure:"exporter"`
+}
+
+type SpanExporter struct {
+    // OTLP corresponds to the JSON schema field "otlp".
+    OTLP *OTLP `json:"otlp,omitempty" yaml:"otlp,omitempty" mapstructure:"otlp,omitempty"`
+}
+
+type LogRecordProcessor struct {
+    // Batch corresponds to the JSON schema field "batch".
+    Batch *BatchLogRecordProcessor `json:"batch,omitempty" yaml:"batch,omitempty" mapstructure:"batch,omitempty"`
+}
+
+type BatchLogRecordProcessor struct {
+    // Exporter corresponds to the JSON schema field "exporter".
+    Exporter LogRecordExporter `json:"exporter" yaml:"exporter" mapstructure:"exporter"`
+}
+
+type LogRecordExporter struct {
+    // OTLP corresponds to the JSON schema field "otlp".
+    OTLP *OTLP `json:"otlp,omitempty" yaml:"otlp,omitempty" mapstructure:"otlp,omitempty"`
+}
+
+type OTLP struct {
+    // Endpoint corresponds to the JSON schema field "endpoint".
+    Endpoint *string `json:"endpoint,omitempty" yaml:"endpoint,omitempty" mapstructure:"endpoint,omitempty"`
+    // Protocol corresponds to the JSON schema field "protocol".
+    Protocol *string `json:"protocol,omitempty" yaml:"protocol,omitempty" mapstructure:"protocol,omitempty"`
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+    "go/ast"
+    "go/constant"
+    "go/token"
+    "go/types"
+    "path/filepath"
+    "sort"
+    "strings"
+
+    packages "golang.org/x/tools/go/packages"
+)
+
+// packagesLoadMode is the go/packages mode for loadPackage and prewarmPackageCache.
+// --typed adds type information so field kinds come from go/types instead of AST spelling.
+func packagesLoadMode() packages.LoadMode {
+    mode := packages.NeedName | packages.NeedFiles | packages.NeedSyntax
+    if *typed {
+        mode |= packages.NeedImports | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedDeps
+    }
+    return mode
+}
+
+// unwrapFieldType strips pointers and aliases and, like resolveStructFromExprWithCtx for
+// Option[T], descends into the last type argument of a generic instance.
+func unwrapFieldType(t types.Type) types.Type {
+    for i := 0; i < 8 && t != nil; i++ {
+        t = types.Unalias(t)
+        switch tt := t.(type) {
+        case *types.Pointer:
+            t = tt.Elem()
+            continue
+        case *types.Named:
+            if args := tt.TypeArgs(); args != nil && args.Len() > 0 {
+                t = args.At(args.Len() - 1)
+                continue
+            }
+        }
+        return t
+    }
+    return t
+}
+
+// typedFieldType returns the checked type of a field expression, or nil when the
+// package was loaded without types or the type did not resolve.
+func typedFieldType(ctx *packageContext, expr ast.Expr) types.Type {
+    if ctx == nil || ctx.tinfo == nil { return nil }
+    t := ctx.tinfo.TypeOf(expr)
+    if t == nil { return nil }
+    if b, ok := t.Underlying().(*types.Basic); ok && b.Kind() == types.Invalid { return nil }
+    return t
+}
+
+// typedStructTarget resolves a field to the AST of its struct type via go/types. known is
+// false when type information is unavailable; then callers fall back to AST resolution.
+func typedStructTarget(ctx *packageContext, expr ast.Expr) (nextCtx *packageContext, st *ast.StructType, known bool) {
+    t := typedFieldType(ctx, expr)
+    if t == nil { return nil, nil, false }
+    t = unwrapFieldType(t)
+    if _, ok := t.Underlying().(*types.Struct); !ok { return nil, nil, true }
+    named, ok := t.(*types.Named)
+    if !ok || named.Obj().Pkg() == nil { return nil, nil, false } // anonymous struct: AST handles it
+    obj := named.Origin().Obj()
+    pc := ctx
+    if ctx.tpkg == nil || obj.Pkg().Path() != ctx.tpkg.Path() { pc = resolveExternalPackage(ctx, obj.Pkg().Path()) }
+    if pc == nil { return nil, nil, false }
+    if st, ok := pc.types[obj.Name()]; ok { return pc, st, true }
+    return nil, nil, false
+}
+
+// typedFieldKind classifies a leaf field from its go/types type. ok is false when type
+// information is unavailable and the AST heuristics should be used instead.
+func typedFieldKind(ctx *packageContext, expr ast.Expr) (kind, itemType string, enumValues []string, ok bool) {
+    t := typedFieldType(ctx, expr)
+    if t == nil { return "", "", nil, false }
+    t = unwrapFieldType(t)
+    if named, isNamed := t.(*types.Named); isNamed {
+        obj := named.Obj()
+        if obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Duration" { return "duration", "", nil, true }
+        if vals := typedEnumValues(named); len(vals) > 0 { return "enum", "", vals, true }
+    }
+    switch u := t.Underlying().(type) {
+    case *types.Basic:
+        return basicKind(u), "", nil, true
+    case *types.Slice:
+        elem := unwrapFieldType(u.Elem())
+        if b, isBasic := elem.Underlying().(*types.Basic); isBasic {
+            // Element enum tokens are kept so list entries can be checked too
+            var vals []string
+            if named, isNamed := elem.(*types.Named); isNamed { vals = typedEnumValues(named) }
+            if b.Info()&types.IsString != 0 { return "stringArray", "", vals, true }
+            return "array", basicKind(b), vals, true
+        }
+        // Structs without exported fields (e.g. component.ID) decode from scalars, not objects
+        if st, isStruct := elem.Underlying().(*types.Struct); isStruct && hasExportedTypedFields(st) { return "array", "object", nil, true }
+        return "array", "", nil, true
+    case *types.Array:
+        return "array", "", nil, true
+    case *types.Map:
+        k, kok := u.Key().Underlying().(*types.Basic)
+        v, vok := unwrapFieldType(u.Elem()).Underlying().(*types.Basic)
+        if kok && vok && k.Info()&types.IsString != 0 && v.Info()&types.IsString != 0 { return "stringMap", "", nil, true }
+        return "map", "", nil, true
+    }
+    return "custom", "", nil, true
+}
+
+func hasExportedTypedFields(st *types.Struct) bool {
+    for i := 0; i < st.NumFields(); i++ {
+        if st.Field(i).Exported() || st.Field(i).Embedded() { return true }
+    }
+    return false
+}
+
+func basicKind(b *types.Basic) string {
+    info := b.Info()
+    switch {
+    case info&types.IsString != 0:
+        return "string"
+    case info&types.IsBoolean != 0:
+        return "bool"
+    case info&types.IsInteger != 0:
+        return "int"
+    case info&types.IsFloat != 0:
+        return "double"
+    }
+    return "custom"
+}
+
+// typedEnumValues lists the YAML tokens of the exported constants declared with a named
+// basic type in its package, in declaration order: literal values for string types, and for
+// integer types whose pointer implements encoding.TextUnmarshaler the constant name without
+// the type name (see constantToken). Other integer types decode from numbers only.
+func typedEnumValues(named *types.Named) []string {
+    b, ok := named.Underlying().(*types.Basic)
+    if !ok || named.Obj().Pkg() == nil { return nil }
+    isString := b.Info()&types.IsString != 0
+    if !isString && (b.Info()&types.IsInteger == 0 || !implementsTextUnmarshaler(named)) { return nil }
+    scope := named.Obj().Pkg().Scope()
+    var consts []*types.Const
+    for _, name := range scope.Names() {
+        c, ok := scope.Lookup(name).(*types.Const)
+        if ok && c.Exported() && types.Identical(c.Type(), named) { consts = append(consts, c) }
+    }
+    sort.SliceStable(consts, func(i, j int) bool { return consts[i].Pos() < consts[j].Pos() })
+    seen := map[string]bool{}
+    var out []string
+    for _, c := range consts {
+        var tok string
+        if isString {
+            if c.Val().Kind() == constant.String { tok = constant.StringVal(c.Val()) }
+        } else {
+            tok = constantToken(c.Name(), named.Obj().Name())
+        }
+        if tok == "" || seen[tok] { continue }
+        seen[tok] = true
+        out = append(out, tok)
+    }
+    // A single constant is usually a default, not a closed set
+    if len(out) < 2 { return nil }
+    return out
+}
+
+// implementsTextUnmarshaler reports whether *T has an UnmarshalText([]byte) error method.
+func implementsTextUnmarshaler(named *types.Named) bool {
+    obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(named), true, named.Obj().Pkg(), "UnmarshalText")
+    fn, ok := obj.(*types.Func)
+    if !ok { return false }
+    sig := fn.Type().(*types.Signature)
+    return sig.Params().Len() == 1 && sig.Results().Len() == 1 && types.Identical(sig.Params().At(0).Type(), types.NewSlice(types.Typ[types.Byte]))
+}
+
+// newPackageContext indexes a loaded package's imports, struct types and other named types.
+func newPackageContext(p *packages.Package, dir string) *packageContext {
+    imports := map[string]string{}
+    structs := map[string]*ast.StructType{}
+    aliases := map[string]ast.Expr{}
+    for _, file := range p.Syntax {
+        for _, is := range file.Imports {
+            path := strings.Trim(is.Path.Value, "\"")
+            alias := ""
+            if is.Name != nil { alias = is.Name.Name } else {
+                parts := strings.Split(path, "/")
+                alias = parts[len(parts)-1]
+            }
+            imports[alias] = path
+        }
+        for _, decl := range file.Decls {
+            gd, ok := decl.(*ast.GenDecl)
+            if !ok || gd.Tok != token.TYPE { continue }
+            for _, spec := range gd.Specs {
+                ts, ok := spec.(*ast.TypeSpec)
+                if !ok { continue }
+                switch tt := ts.Type.(type) {
+                case *ast.StructType:
+                    structs[ts.Name.Name] = tt
+                default:
+                    aliases[ts.Name.Name] = tt
+                }
+            }
+        }
+    }
+    return &packageContext{dir: dir, path: p.PkgPath, files: p.Syntax, fset: p.Fset, imports: imports, types: structs, aliases: aliases,
+        importCache: map[string]*packageContext{}, tpkg: p.Types, tinfo: p.TypesInfo}
+}
+
+// cacheTypedDeps indexes the type-checked dependencies of a package loaded with --typed
+// so later external lookups reuse them instead of type-checking again.
+func cacheTypedDeps(root *packages.Package) {
+    packages.Visit([]*packages.Package{root}, nil, func(p *packages.Package) {
+        if p == root || p.PkgPath == "" || len(p.Syntax) == 0 || len(p.GoFiles) == 0 { return }
+        globalPkgCache.mu.Lock()
+        if _, ok := globalPkgCache.byImport[p.PkgPath]; !ok {
+            globalPkgCache.byImport[p.PkgPath] = newPackageContext(p, filepath.Dir(p.GoFiles[0]))
+        }
+        globalPkgCache.mu.Unlock()
+    })
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+    "go/ast"
+    "go/token"
+    "strings"
+)
+
+// customUnmarshaler describes a config struct that overrides mapstructure decoding:
+// Unmarshal(*confmap.Conf) error (confmap.Unmarshaler) or UnmarshalText, which lets a
+// scalar stand in for the whole struct (string shorthand).
+type customUnmarshaler struct {
+    // keys read directly from the Conf via Get/Sub/IsSet, as path tokens relative to the struct
+    keys [][]string
+}
+
+// confKeyMethods are the confmap.Conf accessors whose first argument is a config key.
+var confKeyMethods = map[string]bool{"Get": true, "Sub": true, "IsSet": true}
+
+// findCustomUnmarshal returns the custom decoding declared on the named type in ctx, or nil.
+func findCustomUnmarshal(ctx *packageContext, typeName string) *customUnmarshaler {
+    if ctx == nil || typeName == "" { return nil }
+    var cu *customUnmarshaler
+    for _, f := range ctx.files {
+        for _, d := range f.Decls {
+            fd, ok := d.(*ast.FuncDecl)
+            if !ok || fd.Recv == nil || len(fd.Recv.List) == 0 || receiverTypeName(fd.Recv.List[0].Type) != typeName { continue }
+            switch fd.Name.Name {
+            case "UnmarshalText":
+                if cu == nil { cu = &customUnmarshaler{} }
+            case "Unmarshal":
+                conf := confParamName(fd)
+                if conf == "" { continue }
+                if cu == nil { cu = &customUnmarshaler{} }
+                cu.keys = append(cu.keys, confKeysRead(ctx, fd.Body, conf)...)
+            }
+        }
+    }
+    return cu
+}
+
+func receiverTypeName(expr ast.Expr) string {
+    switch t := expr.(type) {
+    case *ast.StarExpr:
+        return receiverTypeName(t.X)
+    case *ast.Ident:
+        return t.Name
+    case *ast.IndexExpr:
+        return receiverTypeName(t.X)
+    case *ast.IndexListExpr:
+        return receiverTypeName(t.X)
+    }
+    return ""
+}
+
+// confParamName returns the name of the *confmap.Conf parameter of an Unmarshal method.
+func confParamName(fd *ast.FuncDecl) string {
+    if fd.Type.Params == nil || len(fd.Type.Params.List) != 1 { return "" }
+    p := fd.Type.Params.List[0]
+    star, ok := p.Type.(*ast.StarExpr)
+    if !ok || len(p.Names) != 1 { return "" }
+    if sel, ok := star.X.(*ast.SelectorExpr); !ok || sel.Sel.Name != "Conf" { return "" }
+    return p.Names[0].Name
+}
+
+// confKeysRead collects literal (or constant) keys passed to conf.Get/Sub/IsSet.
+// Keys use the confmap delimiter ("protocols::grpc").
+func confKeysRead(ctx *packageContext, body *ast.BlockStmt, conf string) [][]string {
+    if body == nil { return nil }
+    var out [][]string
+    seen := map[string]bool{}
+    ast.Inspect(body, func(n ast.Node) bool {
+        call, ok := n.(*ast.CallExpr)
+        if !ok || len(call.Args) == 0 { return true }
+        sel, ok := call.Fun.(*ast.SelectorExpr)
+        if !ok || !confKeyMethods[sel.Sel.Name] { return true }
+        if x, ok := sel.X.(*ast.Ident); !ok || x.Name != conf { return true }
+        key := ""
+        switch a := call.Args[0].(type) {
+        case *ast.BasicLit:
+            if a.Kind == token.STRING { key, _ = callArgString(call) }
+        case *ast.Ident:
+            if v, ok := resolveTopLevelIdent(ctx, a.Name); ok {
+                if s, ok := v.(string); ok { key = s }
+            }
+        }
+        if key == "" || seen[key] { return true }
+        seen[key] = true
+        out = append(out, strings.Split(key, "::"))
+        return true
+    })
+    return out
+}
+
+// markCustomUnmarshal flags the fields extracted from a custom-decoded struct and adds
+// the keys its Unmarshal reads directly that no extracted field covers.
+func markCustomUnmarshal(cu *customUnmarshaler, prefix string, fields []ConfigField, out *[]ConfigField) {
+    for i := range fields { fields[i].CustomUnmarshal = true }
+    base := makePathTokens(prefix)
+    for _, k := range cu.keys {
+        tokens := append(append([]string(nil), base...), k...)
+        covered := false
+        for i := range fields {
+            if hasTokenPrefix(fields[i].PathTokens, tokens) { covered = true; break }
+        }
+        if covered { continue }
+        *out = append(*out, ConfigField{
+            Type:            "custom",
+            MapStructure:    strings.Join(tokens, "."),
+            Description:     "Read directly by the component's custom Unmarshal.",
+            PathTokens:      tokens,
+            CustomUnmarshal: true,
+        })
+    }
+}
+
+func hasTokenPrefix(tokens, prefix []string) bool {
+    if len(tokens) < len(prefix) { return false }
+    for i := range prefix {
+        if tokens[i] != prefix[i] { return false }
+    }
+    return true
+}
@@ -0,0 +1,720 @@
+package main
+
+import (
+    "encoding/json"
+    "flag"
+    "fmt"
+    "io"
+    "os"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+
+    "gopkg.in/yaml.v3"
+)
+
+// componentSections maps top-level collector config sections to component types.
+var componentSections = []struct {
+    section string
+    kind    string
+}{
+    {"receivers", "receiver"},
+    {"processors", "processor"},
+    {"exporters", "exporter"},
+    {"connectors", "connector"},
+    {"extensions", "extension"},
+}
+
+// ValidationIssue is a single finding with the position of the offending YAML node.
+type ValidationIssue struct {
+    File      string `json:"file"`
+    Line      int    `json:"line"`
+    Column    int    `json:"column"`
+    Severity  string `json:"severity"` // error, warning
+    Component string `json:"component,omitempty"` // e.g. receivers::otlp/2
+    Key       string `json:"key,omitempty"`
+    Message   string `json:"message"`
+}
+
+func (v ValidationIssue) String() string {
+    prefix := fmt.Sprintf("%s:%d:%d: %s:", v.File, v.Line, v.Column, v.Severity)
+    if v.Component != "" { prefix += " " + v.Component + ":" }
+    if v.Key != "" { prefix += " " + v.Key + ":" }
+    return prefix + " " + v.Message
+}
+
+// schemaNode is a trie over field PathTokens. A node with a field is a leaf
+// setting; nodes without one are nested mappings (or array items under "[]"
+// and map values under "{key}").
+type schemaNode struct {
+    field    *ConfigField
+    children map[string]*schemaNode
+    // custom is set on mappings decoded by a custom Unmarshal; mismatches there are only warnings
+    custom   bool
+}
+
+func (n *schemaNode) child(tok string) *schemaNode {
+    if n.children == nil { n.children = map[string]*schemaNode{} }
+    c := n.children[tok]
+    if c == nil {
+        c = &schemaNode{}
+        n.children[tok] = c
+    }
+    return c
+}
+
+// buildSchemaTree indexes a component's fields by path. Collapsed array fields
+// (tokens ending in "[]" with type "array") describe their parent key.
+func buildSchemaTree(fields []ConfigField) *schemaNode {
+    root := &schemaNode{}
+    for i := range fields {
+        f := &fields[i]
+        tokens := f.PathTokens
+        if len(tokens) == 0 { tokens = makePathTokens(fieldKey(f)) }
+        if n := len(tokens); n > 1 && tokens[n-1] == "[]" && f.Type == "array" {
+            tokens = tokens[:n-1]
+        }
+        node, parent := root, root
+        for _, t := range tokens { parent, node = node, node.child(t) }
+        if node.field == nil { node.field = f }
+        if f.CustomUnmarshal { parent.custom = true }
+    }
+    return root
+}
+
+// schemaIndex resolves component IDs from a config file to extracted components.
+type schemaIndex struct {
+    data       *ExtractedData
+    components map[string]*Component // <type>/<name>
+    trees      map[string]*schemaNode
+}
+
+func newSchemaIndex(data *ExtractedData) *schemaIndex {
+    idx := &schemaIndex{data: data, components: map[string]*Component{}, trees: map[string]*schemaNode{}}
+    for i := range data.Components {
+        c := &data.Components[i]
+        idx.components[componentKey(c)] = c
+    }
+    if data.Service != nil { idx.components[componentKey(data.Service)] = data.Service }
+    return idx
+}
+
+// component returns the component for a section entry ID such as "otlp/2".
+func (idx *schemaIndex) component(kind, id string) *Component {
+    return idx.components[kind+"/"+componentTypeFromID(id)]
+}
+
+func (idx *schemaIndex) tree(c *Component) *schemaNode {
+    k := componentKey(c)
+    if t := idx.trees[k]; t != nil { return t }
+    t := buildSchemaTree(c.Config.Fields)
+    idx.trees[k] = t
+    return t
+}
+
+// componentTypeFromID strips the optional instance suffix: "otlp/2" -> "otlp".
+func componentTypeFromID(id string) string {
+    if i := strings.Index(id, "/"); i >= 0 { return id[:i] }
+    return id
+}
+
+// runValidate implements: validate --schema=configs.json [--format=text|json] <config.yaml>...
+func runValidate(args []string) int {
+    fs := flag.NewFlagSet("validate", flag.ExitOnError)
+    schemaPath := fs.String("schema", "", "Extracted configs JSON to validate against")
+    format := fs.String("format", "text", "Output format: text or json")
+    minStability := fs.String("min-stability", "", "Reject components below this stability level ("+strings.Join(stabilityLevels, ", ")+")")
+    distribution := fs.String("distribution", "", "Warn about components the official distribution does not ship (e.g. otelcol, otelcol-contrib)")
+    envFile := fs.String("env-file", "", "KEY=VALUE file expanding ${env:VAR} and ${VAR} references (otherwise only their :- defaults are checked)")
+    var overrides []configOverride
+    fs.Func("set", "Override a value like the collector's --set, e.g. processors::batch::timeout=2s (repeatable)", func(s string) error {
+        o, err := parseConfigOverride(s)
+        if err == nil { overrides = append(overrides, o) }
+        return err
+    })
+    fs.Usage = func() {
+        fmt.Fprintln(os.Stderr, "Usage: go run . validate --schema=configs.json [--format=text|json] [--min-stability=beta] [--distribution=otelcol] [--env-file=.env] [--set=key::path=value]... <collector.yaml>...")
+        fs.PrintDefaults()
+    }
+    _ = fs.Parse(args)
+    if *schemaPath == "" || fs.NArg() == 0 {
+        fs.Usage()
+        return 2
+    }
+    if *minStability != "" && stabilityRank(*minStability) < 0 {
+        fmt.Fprintf(os.Stderr, "validate: unknown stability level %q\n", *minStability)
+        return 2
+    }
+    opts := validateOptions{minStability: *minStability, distribution: *distribution, overrides: overrides}
+    if *envFile != "" {
+        env, err := readEnvFile(*envFile)
+        if err != nil {
+            fmt.Fprintf(os.Stderr, "validate: %v\n", err)
+            return 2
+        }
+        opts.env = env
+    }
+    data, err := loadExtractedData(*schemaPath)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "validate: %v\n", err)
+        return 2
+    }
+    if *distribution != "" && !containsToken(data.Releases, *distribution) {
+        if len(data.Releases) == 0 {
+            fmt.Fprintf(os.Stderr, "validate: %s has no distribution manifests (extract with --releases-path)\n", *schemaPath)
+        } else {
+            fmt.Fprintf(os.Stderr, "validate: unknown distribution %q (known: %s)\n", *distribution, strings.Join(data.Releases, ", "))
+        }
+        return 2
+    }
+    idx := newSchemaIndex(data)
+    var issues []ValidationIssue
+    for _, path := range fs.Args() {
+        content, err := os.ReadFile(path)
+        if err != nil {
+            fmt.Fprintf(os.Stderr, "validate: %v\n", err)
+            return 2
+        }
+        found, err := validateConfig(idx, path, content, opts)
+        if err != nil {
+            fmt.Fprintf(os.Stderr, "validate: %s: %v\n", path, err)
+            return 2
+        }
+        issues = append(issues, found...)
+    }
+    if err := writeIssues(os.Stdout, *format, issues); err != nil {
+        fmt.Fprintf(os.Stderr, "validate: %v\n", err)
+        return 2
+    }
+    for _, is := range issues {
+        if is.Severity == "error" { return 1 }
+    }
+    return 0
+}
+
+func writeIssues(w io.Writer, format string, issues []ValidationIssue) error {
+    switch format {
+    case "json":
+        if issues == nil { issues = []ValidationIssue{} }
+        data, err := json.MarshalIndent(issues, "", "  ")
+        if err != nil { return err }
+        fmt.Fprintln(w, string(data))
+    case "text":
+        for _, is := range issues { fmt.Fprintln(w, is.String()) }
+    default:
+        return fmt.Errorf("unknown format %q", format)
+    }
+    return nil
+}
+
+// parseConfigYAML returns the root mapping of a collector config document.
+func parseConfigYAML(content []byte) (*yaml.Node, error) {
+    var doc yaml.Node
+    if err := yaml.Unmarshal(content, &doc); err != nil { return nil, err }
+    if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 { return nil, nil }
+    root := doc.Content[0]
+    if root.Kind != yaml.MappingNode { return nil, fmt.Errorf("line %d: top-level document is not a mapping", root.Line) }
+    return root, nil
+}
+
+// mappingValue returns the key and value nodes for key in a mapping node.
+func mappingValue(m *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+    if m == nil || m.Kind != yaml.MappingNode { return nil, nil }
+    for i := 0; i+1 < len(m.Content); i += 2 {
+        if m.Content[i].Value == key { return m.Content[i], m.Content[i+1] }
+    }
+    return nil, nil
+}
+
+// validateOptions are the optional checks enabled from the validate command line.
+type validateOptions struct {
+    minStability string // reject pipeline uses below this level; "" disables the check
+    distribution string // warn about components this release does not ship; "" disables the check
+    env          map[string]string // --env-file variables; nil leaves references without a default unchecked
+    overrides    []configOverride  // --set values merged into each file before validation
+}
+
+// Component stability levels, least to most stable.
+var stabilityLevels = []string{"unmaintained", "deprecated", "development", "alpha", "beta", "stable"}
+
+func stabilityRank(level string) int {
+    for i, l := range stabilityLevels {
+        if l == level { return i }
+    }
+    return -1
+}
+
+// validateConfig checks every component entry of a collector config against the schema.
+func validateConfig(idx *schemaIndex, file string, content []byte, opts validateOptions) ([]ValidationIssue, error) {
+    root, err := parseConfigYAML(content)
+    if err != nil || root == nil { return nil, err }
+    v := &configValidator{idx: idx, file: file, opts: opts}
+    v.overridden = applyConfigOverrides(root, opts.overrides)
+    for _, sec := range componentSections {
+        _, entries := mappingValue(root, sec.section)
+        if entries == nil || entries.Kind != yaml.MappingNode { continue }
+        for i := 0; i+1 < len(entries.Content); i += 2 {
+            idNode, cfgNode := entries.Content[i], entries.Content[i+1]
+            v.component = sec.section + "::" + idNode.Value
+            c := idx.component(sec.kind, idNode.Value)
+            if c == nil {
+                v.report(idNode, "error", "", fmt.Sprintf("unknown %s type %q", sec.kind, componentTypeFromID(idNode.Value)))
+                continue
+            }
+            v.checkDistribution(c, idNode)
+            v.validateComponent(c, idNode, cfgNode)
+        }
+    }
+    // The service section is checked like a component when its schema was extracted
+    if svc := idx.data.Service; svc != nil {
+        if keyNode, cfgNode := mappingValue(root, "service"); cfgNode != nil {
+            v.component = "service"
+            v.validateComponent(svc, keyNode, cfgNode)
+        }
+    }
+    v.component = ""
+    v.validatePipelines(root)
+    sort.SliceStable(v.issues, func(i, j int) bool {
+        if v.issues[i].Line != v.issues[j].Line { return v.issues[i].Line < v.issues[j].Line }
+        return v.issues[i].Column < v.issues[j].Column
+    })
+    return v.issues, nil
+}
+
+type configValidator struct {
+    idx       *schemaIndex
+    file      string
+    opts      validateOptions
+    component string
+    issues    []ValidationIssue
+    // overridden maps values inserted by --set to their flag
+    overridden map[*yaml.Node]string
+}
+
+func (v *configValidator) report(n *yaml.Node, severity, key, msg string) {
+    if arg, ok := v.overridden[n]; ok { msg += " (from --set " + arg + ")" }
+    v.issues = append(v.issues, ValidationIssue{File: v.file, Line: n.Line, Column: n.Column, Severity: severity, Component: v.component, Key: key, Message: msg})
+}
+
+func (v *configValidator) validateComponent(c *Component, idNode, cfgNode *yaml.Node) {
+    tree := v.idx.tree(c)
+    v.walk(tree, cfgNode, nil)
+    v.checkRequired(c, idNode, cfgNode)
+    v.checkConstraints(c, idNode, cfgNode)
+}
+
+// walk validates a YAML value against a schema node.
+func (v *configValidator) walk(sn *schemaNode, n *yaml.Node, path []string) {
+    if n == nil || isNullNode(n) { return }
+    if n.Kind == yaml.AliasNode && n.Alias != nil { n = n.Alias }
+    key := strings.Join(path, ".")
+    if sn.field != nil {
+        if f := sn.field; f.Deprecated {
+            msg := "deprecated key"
+            if f.Replacement != "" { msg += "; use " + f.Replacement + " instead" }
+            v.report(n, "warning", key, msg)
+        }
+        v.checkLeaf(sn.field, n, key)
+        // Arrays and maps of objects with known item fields are checked entry by entry
+        if items := sn.children["[]"]; items != nil && len(items.children) > 0 && n.Kind == yaml.SequenceNode {
+            for _, it := range n.Content { v.walkEntry(items, it, append(append([]string{}, path...), "[]")) }
+        }
+        if values := sn.children["{key}"]; values != nil && n.Kind == yaml.MappingNode {
+            for i := 0; i+1 < len(n.Content); i += 2 {
+                v.walkEntry(values, n.Content[i+1], append(append([]string{}, path...), n.Content[i].Value))
+            }
+        }
+        return
+    }
+    switch n.Kind {
+    case yaml.MappingNode:
+        for i := 0; i+1 < len(n.Content); i += 2 {
+            k, val := n.Content[i], n.Content[i+1]
+            child := sn.children[k.Value]
+            if child == nil { child = sn.children["{key}"] }
+            if child == nil {
+                if sn.custom {
+                    v.report(k, "warning", joinKey(key, k.Value), "unknown key (decoded by a custom Unmarshal; the schema may be incomplete)")
+                    continue
+                }
+                v.report(k, "error", joinKey(key, k.Value), "unknown key")
+                continue
+            }
+            v.walk(child, val, append(append([]string{}, path...), k.Value))
+        }
+    case yaml.SequenceNode:
+        if items := sn.children["[]"]; items != nil {
+            for _, it := range n.Content { v.walkEntry(items, it, append(append([]string{}, path...), "[]")) }
+            return
+        }
+        v.report(n, "error", key, "expected a mapping, got a sequence")
+    default:
+        // A scalar where only array items are known (e.g. component references) is accepted.
+        if len(sn.children) == 1 && sn.children["[]"] != nil { return }
+        // UnmarshalText / custom Unmarshal may accept a scalar shorthand for the whole struct
+        if sn.custom { return }
+        // A reference may expand to the whole mapping (${file:...}, ...)
+        if hasConfmapReference(n.Value) { return }
+        if len(path) == 0 {
+            v.report(n, "error", key, "expected a mapping for the component configuration")
+            return
+        }
+        v.report(n, "error", key, fmt.Sprintf("expected a mapping, got %s", describeNode(n)))
+    }
+}
+
+// walkEntry validates one array item or map value, including its required keys
+// (checkRequired only covers keys outside collections).
+func (v *configValidator) walkEntry(sn *schemaNode, n *yaml.Node, path []string) {
+    v.walk(sn, n, path)
+    if n.Kind == yaml.AliasNode && n.Alias != nil { n = n.Alias }
+    if n.Kind != yaml.MappingNode { return }
+    for _, k := range sortedKeys(sn.children) {
+        f := sn.children[k].field
+        if f == nil || !f.Required || f.Default != nil { continue }
+        if _, val := mappingValue(n, k); val == nil {
+            v.report(n, "error", joinKey(strings.Join(path, "."), k), "missing required key")
+        }
+    }
+}
+
+func joinKey(prefix, k string) string {
+    if prefix == "" { return k }
+    return prefix + "." + k
+}
+
+func isNullNode(n *yaml.Node) bool {
+    return n.Kind == yaml.ScalarNode && n.Tag == "!!null"
+}
+
+func describeNode(n *yaml.Node) string {
+    switch n.Kind {
+    case yaml.MappingNode:
+        return "a mapping"
+    case yaml.SequenceNode:
+        return "a sequence"
+    default:
+        return fmt.Sprintf("%s %q", strings.TrimPrefix(n.ShortTag(), "!!"), n.Value)
+    }
+}
+
+// checkLeaf validates a value against the field's Type, EnumValues and Validation bounds.
+func (v *configValidator) checkLeaf(f *ConfigField, n *yaml.Node, key string) {
+    // A custom Unmarshal may accept other shapes or values, so mismatches are advisory
+    severity, note := "error", ""
+    if f.CustomUnmarshal { severity, note = "warning", " (decoded by a custom Unmarshal)" }
+    // ${...} references are checked by what they expand to, when that is known offline
+    if n.Kind == yaml.ScalarNode && hasConfmapReference(n.Value) {
+        expanded := v.expandScalar(n, key)
+        if expanded == nil { return }
+        if arg, ok := v.overridden[n]; ok { v.overridden[expanded] = arg }
+        note += fmt.Sprintf(" (expanded from %q)", n.Value)
+        n = expanded
+    }
+    if msg := typeMismatch(f, n); msg != "" {
+        v.report(n, severity, key, msg+note)
+        return
+    }
+    if f.Type == "enum" && len(f.EnumValues) > 0 && n.Kind == yaml.ScalarNode && !enumContains(f.EnumValues, n.Value) {
+        v.report(n, severity, key, fmt.Sprintf("invalid value %q; expected one of: %s", n.Value, strings.Join(f.EnumValues, ", "))+note)
+        return
+    }
+    for _, msg := range boundsViolations(f, n) {
+        // Bounds are inferred from Validate() bodies, so they are advisory.
+        v.report(n, "warning", key, msg)
+    }
+}
+
+// typeMismatch returns a message when the YAML node cannot decode into the field's type.
+func typeMismatch(f *ConfigField, n *yaml.Node) string {
+    tag := n.ShortTag()
+    switch f.Type {
+    case "bool":
+        if n.Kind != yaml.ScalarNode || tag != "!!bool" { return "expected a bool, got " + describeNode(n) }
+    case "int":
+        if n.Kind != yaml.ScalarNode || tag != "!!int" { return "expected an integer, got " + describeNode(n) }
+    case "double":
+        if n.Kind != yaml.ScalarNode || (tag != "!!int" && tag != "!!float") { return "expected a number, got " + describeNode(n) }
+    case "duration":
+        if n.Kind != yaml.ScalarNode { return "expected a duration, got " + describeNode(n) }
+        if tag == "!!int" { return "" }
+        if _, err := time.ParseDuration(n.Value); err != nil { return fmt.Sprintf("invalid duration %q", n.Value) }
+    case "string", "enum":
+        if n.Kind != yaml.ScalarNode { return "expected a string, got " + describeNode(n) }
+    case "stringArray":
+        if n.Kind != yaml.SequenceNode { return "expected a list of strings, got " + describeNode(n) }
+        for _, it := range n.Content {
+            if it.Kind != yaml.ScalarNode { return "expected a list of strings, found " + describeNode(it) }
+        }
+    case "array":
+        if n.Kind == yaml.ScalarNode && f.ItemType == "componentRef" { return "" }
+        if n.Kind != yaml.SequenceNode { return "expected a list, got " + describeNode(n) }
+    case "map", "stringMap":
+        if n.Kind != yaml.MappingNode { return "expected a mapping, got " + describeNode(n) }
+    }
+    return ""
+}
+
+func enumContains(values []string, s string) bool {
+    for _, ev := range values {
+        if ev == s || strings.EqualFold(ev, s) { return true }
+    }
+    return false
+}
+
+// boundsViolations checks numeric/duration values against Validation min/max keys.
+func boundsViolations(f *ConfigField, n *yaml.Node) []string {
+    if len(f.Validation) == 0 || n.Kind != yaml.ScalarNode { return nil }
+    var val float64
+    switch f.Type {
+    case "int", "double":
+        x, err := strconv.ParseFloat(n.Value, 64)
+        if err != nil { return nil }
+        val = x
+    case "duration":
+        if d, err := time.ParseDuration(n.Value); err == nil {
+            val = float64(d)
+        } else if x, err := strconv.ParseFloat(n.Value, 64); err == nil {
+            val = x
+        } else {
+            return nil
+        }
+    default:
+        return nil
+    }
+    var out []string
+    check := func(name string, ok func(b float64) bool, rel string) {
+        s, present := f.Validation[name]
+        if !present { return }
+        b, err := strconv.ParseFloat(s, 64)
+        if err != nil { return }
+        if !ok(b) { out = append(out, fmt.Sprintf("value %s must be %s %s", n.Value, rel, s)) }
+    }
+    check("min", func(b float64) bool { return val >= b }, ">=")
+    check("minExclusive", func(b float64) bool { return val > b }, ">")
+    check("max", func(b float64) bool { return val <= b }, "<=")
+    check("maxExclusive", func(b float64) bool { return val < b }, "<")
+    return out
+}
+
+// lookupPath follows mapping keys and reports the deepest node reached.
+func lookupPath(n *yaml.Node, tokens []string) (*yaml.Node, *yaml.Node, bool) {
+    cur, last := n, n
+    for _, t := range tokens {
+        if cur == nil || cur.Kind != yaml.MappingNode { return nil, last, false }
+        _, val := mappingValue(cur, t)
+        if val == nil { return nil, last, false }
+        last = val
+        cur = val
+    }
+    return cur, last, true
+}
+
+func keyPresent(n *yaml.Node, tokens []string) bool {
+    val, _, ok := lookupPath(n, tokens)
+    return ok && val != nil && !isNullNode(val)
+}
+
+// checkRequired reports required fields without defaults that are absent while their parent is set.
+func (v *configValidator) checkRequired(c *Component, idNode, cfgNode *yaml.Node) {
+    for i := range c.Config.Fields {
+        f := &c.Config.Fields[i]
+        if !f.Required || f.Default != nil || len(f.PathTokens) == 0 { continue }
+        if containsToken(f.PathTokens, "[]") || containsToken(f.PathTokens, "{key}") { continue }
+        parent := f.PathTokens[:len(f.PathTokens)-1]
+        if len(parent) > 0 && !keyPresent(cfgNode, parent) { continue }
+        if keyPresent(cfgNode, f.PathTokens) { continue }
+        at := idNode
+        if len(parent) > 0 {
+            if _, last, _ := lookupPath(cfgNode, parent); last != nil { at = last }
+        }
+        v.report(at, "error", strings.Join(f.PathTokens, "."), "missing required key")
+    }
+}
+
+func containsToken(tokens []string, t string) bool {
+    for _, x := range tokens {
+        if x == t { return true }
+    }
+    return false
+}
+
+// checkConstraints evaluates anyOf/oneOf/atMostOne/allOf key groups.
+func (v *configValidator) checkConstraints(c *Component, idNode, cfgNode *yaml.Node) {
+    for _, cs := range c.Constraints {
+        keys := make([]string, 0, len(cs.KeyTokens))
+        present := 0
+        for _, k := range cs.KeyTokens {
+            keys = append(keys, strings.Join(k, "."))
+            if keyPresent(cfgNode, k) { present++ }
+        }
+        var msg string
+        switch cs.Kind {
+        case "anyOf":
+            if present == 0 { msg = "at least one of " + strings.Join(keys, ", ") + " must be set" }
+        case "oneOf":
+            if present != 1 { msg = "exactly one of " + strings.Join(keys, ", ") + " must be set" }
+        case "atMostOne":
+            if present > 1 { msg = "at most one of " + strings.Join(keys, ", ") + " may be set" }
+        case "allOf":
+            if present != len(keys) { msg = "all of " + strings.Join(keys, ", ") + " must be set" }
+        }
+        if msg == "" { continue }
+        if cs.Message != "" { msg += " (" + cs.Message + ")" }
+        v.report(idNode, "error", "", msg)
+    }
+}
+
+// pipelineUse records one reference to a component from a service pipeline.
+type pipelineUse struct {
+    signal string
+    node   *yaml.Node
+}
+
+// validatePipelines checks service.extensions and service.pipelines references: each ID
+// must be defined, must support the pipeline signal, and connectors must be used with
+// a from/to signal pair they declare.
+func (v *configValidator) validatePipelines(root *yaml.Node) {
+    defined := map[string]map[string]bool{}
+    for _, sec := range componentSections {
+        defined[sec.section] = map[string]bool{}
+        _, entries := mappingValue(root, sec.section)
+        if entries == nil || entries.Kind != yaml.MappingNode { continue }
+        for i := 0; i+1 < len(entries.Content); i += 2 { defined[sec.section][entries.Content[i].Value] = true }
+    }
+    _, service := mappingValue(root, "service")
+    if service == nil { return }
+    if _, exts := mappingValue(service, "extensions"); exts != nil && exts.Kind == yaml.SequenceNode {
+        for _, id := range exts.Content {
+            if !defined["extensions"][id.Value] {
+                v.report(id, "error", "service.extensions", fmt.Sprintf("extension %q is not defined in extensions", id.Value))
+                continue
+            }
+            v.checkStability(v.idx.component("extension", id.Value), id, "service.extensions", "extension")
+        }
+    }
+    _, pipelines := mappingValue(service, "pipelines")
+    if pipelines == nil || pipelines.Kind != yaml.MappingNode { return }
+
+    doc := v.idx.data.Document
+    refs := pipelineRefs(doc)
+    connectorAsExporter := map[string][]pipelineUse{}
+    connectorAsReceiver := map[string][]pipelineUse{}
+    for i := 0; i+1 < len(pipelines.Content); i += 2 {
+        nameNode, pipe := pipelines.Content[i], pipelines.Content[i+1]
+        signal := componentTypeFromID(nameNode.Value)
+        if len(doc.Signals) > 0 && !containsToken(doc.Signals, signal) {
+            v.report(nameNode, "error", "service.pipelines", fmt.Sprintf("unknown signal %q in pipeline %q", signal, nameNode.Value))
+            continue
+        }
+        for _, list := range []string{"receivers", "processors", "exporters"} {
+            _, ids := mappingValue(pipe, list)
+            if ids == nil || ids.Kind != yaml.SequenceNode { continue }
+            key := "service.pipelines." + nameNode.Value + "." + list
+            for _, id := range ids.Content {
+                section := ""
+                for _, candidate := range refs[list] {
+                    if defined[candidate][id.Value] { section = candidate; break }
+                }
+                if section == "" {
+                    v.report(id, "error", key, fmt.Sprintf("%q is not defined in %s", id.Value, strings.Join(refs[list], " or ")))
+                    continue
+                }
+                if section == "connectors" {
+                    use := pipelineUse{signal: signal, node: id}
+                    if list == "exporters" {
+                        connectorAsExporter[id.Value] = append(connectorAsExporter[id.Value], use)
+                    } else {
+                        connectorAsReceiver[id.Value] = append(connectorAsReceiver[id.Value], use)
+                    }
+                    continue
+                }
+                kind := strings.TrimSuffix(section, "s")
+                c := v.idx.component(kind, id.Value)
+                if c == nil { continue }
+                if len(c.Signals) > 0 && !containsToken(c.Signals, signal) {
+                    v.report(id, "error", key, fmt.Sprintf("%s %q does not support %s (supports: %s)", kind, id.Value, signal, strings.Join(c.Signals, ", ")))
+                    continue
+                }
+                v.checkStability(c, id, key, signal)
+            }
+        }
+    }
+    v.checkConnectorUses(connectorAsExporter, connectorAsReceiver)
+}
+
+// pipelineRefs maps each pipeline list to the config sections its IDs may reference.
+func pipelineRefs(doc DocumentSchema) map[string][]string {
+    if len(doc.PipelineRefs) > 0 { return doc.PipelineRefs }
+    return map[string][]string{"receivers": {"receivers", "connectors"}, "processors": {"processors"}, "exporters": {"exporters", "connectors"}}
+}
+
+// checkConnectorUses verifies connectors are wired on both sides with supported signal pairs.
+func (v *configValidator) checkConnectorUses(asExporter, asReceiver map[string][]pipelineUse) {
+    ids := map[string]bool{}
+    for id := range asExporter { ids[id] = true }
+    for id := range asReceiver { ids[id] = true }
+    for _, id := range sortedKeys(ids) {
+        exp, rec := asExporter[id], asReceiver[id]
+        if len(rec) == 0 {
+            v.report(exp[0].node, "error", "service.pipelines", fmt.Sprintf("connector %q is used as an exporter but not as a receiver in any pipeline", id))
+            continue
+        }
+        if len(exp) == 0 {
+            v.report(rec[0].node, "error", "service.pipelines", fmt.Sprintf("connector %q is used as a receiver but not as an exporter in any pipeline", id))
+            continue
+        }
+        c := v.idx.component("connector", id)
+        if c == nil || len(c.SignalPairs) == 0 { continue }
+        supported := map[SignalPair]bool{}
+        for _, p := range c.SignalPairs { supported[p] = true }
+        // Like the collector's pipeline graph: unsupported combinations are skipped, but
+        // every use must pair with at least one supported use on the other side.
+        checked := map[SignalPair]bool{}
+        for _, e := range exp {
+            ok := false
+            for _, r := range rec {
+                p := SignalPair{From: e.signal, To: r.signal}
+                if !supported[p] { continue }
+                ok = true
+                if !checked[p] {
+                    checked[p] = true
+                    v.checkStability(c, e.node, "service.pipelines", p.From+"_to_"+p.To)
+                }
+            }
+            if !ok {
+                v.report(e.node, "error", "service.pipelines", fmt.Sprintf("connector %q used as exporter in a %s pipeline but not used in any supported receiver pipeline", id, e.signal))
+            }
+        }
+        for _, r := range rec {
+            ok := false
+            for _, e := range exp { ok = ok || supported[SignalPair{From: e.signal, To: r.signal}] }
+            if !ok {
+                v.report(r.node, "error", "service.pipelines", fmt.Sprintf("connector %q used as receiver in a %s pipeline but not used in any supported exporter pipeline", id, r.signal))
+            }
+        }
+    }
+}
+
+// checkDistribution warns about a component missing from the --distribution manifest,
+// naming the distributions that do ship it.
+func (v *configValidator) checkDistribution(c *Component, idNode *yaml.Node) {
+    if v.opts.distribution == "" || containsToken(c.Releases, v.opts.distribution) { return }
+    msg := fmt.Sprintf("%s %q is not in %s", c.Type, c.Name, v.opts.distribution)
+    if len(c.Releases) > 0 {
+        msg += " (available in " + strings.Join(c.Releases, ", ") + ")"
+    } else {
+        msg += " (not shipped by any official distribution; build a custom one)"
+    }
+    v.report(idNode, "warning", "", msg)
+}
+
+// checkStability reports a component whose stability for signalKey is below --min-stability.
+// Components without a recorded level for the signal are not reported.
+func (v *configValidator) checkStability(c *Component, n *yaml.Node, key, signalKey string) {
+    if v.opts.minStability == "" || c == nil { return }
+    level := c.Stability[signalKey]
+    if level == "" || stabilityRank(level) >= stabilityRank(v.opts.minStability) { return }
+    v.report(n, "error", key, fmt.Sprintf("%s %q is %s for %s (minimum stability is %s)", c.Type, n.Value, level, strings.ReplaceAll(signalKey, "_", " "), v.opts.minStability))
+}
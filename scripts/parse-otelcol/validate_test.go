@@ -0,0 +1,225 @@
+package main
+
+import (
+    "path/filepath"
+    "testing"
+)
+
+// TestValidateSchema checks component entries against the fixture schema: unknown
+// components and keys, value types, enums, bounds, required keys and key group constraints.
+func TestValidateSchema(t *testing.T) {
+    config := `receivers:
+  otlp:
+    protocols:
+      grpc:
+        max_recv_msg_size_mib: big
+      http:
+        endpoint: 0.0.0.0:4318
+      bogus: 1
+  otlp/2:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317
+        max_recv_msg_size_mib: 512
+processors:
+  batch:
+    timeout: 5 minutes
+    send_batch_size: [1]
+    metadata_keys: tenant
+  batch/2: [timeout]
+exporters:
+  otlp:
+    endpoint: backend:4317
+    compression: brotli
+    token: a
+    api_key: b
+    tls:
+      insecure: yes
+  nosuch: {}
+`
+    issues, err := validateConfig(fixtureIndex(t), "collector.yaml", []byte(config), validateOptions{})
+    if err != nil { t.Fatal(err) }
+    checkIssues(t, issues,
+        "collector.yaml:5:9: error: receivers::otlp: protocols.grpc.endpoint: missing required key",
+        `collector.yaml:5:32: error: receivers::otlp: protocols.grpc.max_recv_msg_size_mib: expected an integer, got str "big"`,
+        "collector.yaml:8:7: error: receivers::otlp: protocols.bogus: unknown key",
+        "collector.yaml:13:32: warning: receivers::otlp/2: protocols.grpc.max_recv_msg_size_mib: value 512 must be <= 256",
+        `collector.yaml:16:14: error: processors::batch: timeout: invalid duration "5 minutes"`,
+        "collector.yaml:17:22: error: processors::batch: send_batch_size: expected an integer, got a sequence",
+        `collector.yaml:18:20: error: processors::batch: metadata_keys: expected a list of strings, got str "tenant"`,
+        "collector.yaml:19:12: error: processors::batch/2: expected a mapping, got a sequence",
+        "collector.yaml:21:3: error: exporters::otlp: exactly one of token, api_key must be set",
+        `collector.yaml:23:18: error: exporters::otlp: compression: invalid value "brotli"; expected one of: gzip, zstd, none`,
+        `collector.yaml:27:17: error: exporters::otlp: tls.insecure: expected a bool, got str "yes"`,
+        `collector.yaml:28:3: error: exporters::nosuch: unknown exporter type "nosuch"`,
+    )
+}
+
+// TestValidatePipelines checks service.extensions and pipeline references: defined IDs,
+// supported signals and connectors wired on both sides with a declared signal pair.
+func TestValidatePipelines(t *testing.T) {
+    config := `receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317
+exporters:
+  otlp:
+    endpoint: backend:4317
+    token: ${env:TOKEN}
+connectors:
+  count:
+  count/unused:
+extensions:
+  health_check:
+service:
+  extensions: [health_check, pprof]
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [otlp, count, count/unused]
+    metrics:
+      receivers: [count]
+      exporters: [otlp]
+    logs:
+      receivers: [otlp, count]
+      processors: [batch]
+      exporters: [otlp, missing]
+    events:
+      receivers: [otlp]
+`
+    issues, err := validateConfig(fixtureIndex(t), "collector.yaml", []byte(config), validateOptions{})
+    if err != nil { t.Fatal(err) }
+    checkIssues(t, issues,
+        `collector.yaml:16:30: error: service.extensions: extension "pprof" is not defined in extensions`,
+        `collector.yaml:20:32: error: service.pipelines: connector "count/unused" is used as an exporter but not as a receiver in any pipeline`,
+        `collector.yaml:25:25: error: service.pipelines: connector "count" used as receiver in a logs pipeline but not used in any supported exporter pipeline`,
+        `collector.yaml:26:20: error: service.pipelines.logs.processors: "batch" is not defined in processors`,
+        `collector.yaml:27:19: error: service.pipelines.logs.exporters: exporter "otlp" does not support logs (supports: metrics, traces)`,
+        `collector.yaml:27:25: error: service.pipelines.logs.exporters: "missing" is not defined in exporters or connectors`,
+        `collector.yaml:28:5: error: service.pipelines: unknown signal "events" in pipeline "events"`,
+    )
+}
+
+// TestValidateMinStability reports pipeline and extension uses below --min-stability,
+// per signal and per connector signal pair.
+func TestValidateMinStability(t *testing.T) {
+    config := `receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317
+processors:
+  batch:
+exporters:
+  otlp:
+    endpoint: backend:4317
+    token: ${env:TOKEN}
+connectors:
+  count:
+extensions:
+  health_check:
+service:
+  extensions: [health_check]
+  pipelines:
+    traces:
+      receivers: [otlp]
+      processors: [batch]
+      exporters: [otlp, count]
+    metrics:
+      receivers: [otlp, count]
+      exporters: [otlp]
+`
+    issues, err := validateConfig(fixtureIndex(t), "collector.yaml", []byte(config), validateOptions{minStability: "beta"})
+    if err != nil { t.Fatal(err) }
+    checkIssues(t, issues,
+        `collector.yaml:17:16: error: service.extensions: extension "health_check" is development for extension (minimum stability is beta)`,
+        `collector.yaml:22:25: error: service.pipelines: connector "count" is alpha for traces to metrics (minimum stability is beta)`,
+        `collector.yaml:25:19: error: service.pipelines.metrics.exporters: exporter "otlp" is alpha for metrics (minimum stability is beta)`,
+    )
+}
+
+// TestValidateCollections checks array items and map values of structs entry by entry,
+// including their required keys.
+func TestValidateCollections(t *testing.T) {
+    config := `processors:
+  routing:
+    table:
+      - statement: route() where attributes["tenant"] == "a"
+        exporters: [otlp]
+      - exporters: otlp
+    tenants:
+      a:
+        endpoint: a.example.com:4317
+        timeout: 1s
+      b:
+        timeout: soon
+        retries: 3
+`
+    issues, err := validateConfig(fixtureIndex(t), "collector.yaml", []byte(config), validateOptions{})
+    if err != nil { t.Fatal(err) }
+    checkIssues(t, issues,
+        "collector.yaml:6:9: error: processors::routing: table.[].statement: missing required key",
+        `collector.yaml:6:20: error: processors::routing: table.[].exporters: expected a list of strings, got str "otlp"`,
+        "collector.yaml:12:9: error: processors::routing: tenants.b.endpoint: missing required key",
+        `collector.yaml:12:18: error: processors::routing: tenants.b.timeout: invalid duration "soon"`,
+        "collector.yaml:13:9: error: processors::routing: tenants.b.retries: unknown key",
+    )
+}
+
+// TestValidateCustomUnmarshal downgrades mismatches under a struct with its own Unmarshal
+// to warnings and accepts a scalar in place of the struct.
+func TestValidateCustomUnmarshal(t *testing.T) {
+    config := `extensions:
+  health_check:
+    check_collector_pipeline:
+      enabled: maybe
+      threshold: 5
+  health_check/2:
+    check_collector_pipeline: on
+    path: /health
+`
+    issues, err := validateConfig(fixtureIndex(t), "collector.yaml", []byte(config), validateOptions{})
+    if err != nil { t.Fatal(err) }
+    checkIssues(t, issues,
+        `collector.yaml:4:16: warning: extensions::health_check: check_collector_pipeline.enabled: expected a bool, got str "maybe" (decoded by a custom Unmarshal)`,
+        "collector.yaml:5:7: warning: extensions::health_check: check_collector_pipeline.threshold: unknown key (decoded by a custom Unmarshal; the schema may be incomplete)",
+        "collector.yaml:8:5: error: extensions::health_check/2: path: unknown key",
+    )
+}
+
+// TestValidateDeprecated warns about deprecated keys and names their replacement.
+func TestValidateDeprecated(t *testing.T) {
+    config := `receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317
+        max_recv_msg_size: 4194304
+`
+    issues, err := validateConfig(fixtureIndex(t), "collector.yaml", []byte(config), validateOptions{})
+    if err != nil { t.Fatal(err) }
+    checkIssues(t, issues,
+        "collector.yaml:6:28: warning: receivers::otlp: protocols.grpc.max_recv_msg_size: deprecated key; use protocols.grpc.max_recv_msg_size_mib instead",
+    )
+}
+
+// TestValidateDistribution warns about components --distribution does not ship.
+func TestValidateDistribution(t *testing.T) {
+    _, components := fixtureComponents(t)
+    byModule, releases := readReleaseManifests(filepath.Join("testdata", "releases"))
+    applyReleaseDistributions(components, byModule)
+    idx := newSchemaIndex(&ExtractedData{Components: components, Releases: releases})
+    config := `receivers:
+  optional:
+  proto:
+processors:
+  mutate:
+`
+    issues, err := validateConfig(idx, "collector.yaml", []byte(config), validateOptions{distribution: "otelcol"})
+    if err != nil { t.Fatal(err) }
+    checkIssues(t, issues,
+        `collector.yaml:3:3: warning: receivers::proto: receiver "proto" is not in otelcol (available in otelcol-contrib)`,
+        `collector.yaml:5:3: warning: processors::mutate: processor "mutate" is not in otelcol (not shipped by any official distribution; build a custom one)`,
+    )
+}